@@ -0,0 +1,69 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
+	"github.com/spf13/cobra"
+)
+
+var entitlementsCmd = &cobra.Command{
+	Use:   `entitlements`,
+	Short: `Checks the macOS entitlements files and compares them with the recommended ones`,
+	Long: `Checks the macOS entitlements files configured in the parameters for invalid and misspelled keys,
+hardened runtime exceptions Chromium does not need, and keys that require a provisioning profile,
+and compares the exceptions with the recommended ones for every kind of code in the app bundle`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed(jsonPathFlag) {
+			return errors.New("missing flag: " + jsonPathFlag)
+		}
+		params, err := common.GetBrandingParams(jsonPath)
+		if err != nil {
+			return fmt.Errorf("could not obtain branding info: %w", err)
+		}
+		reports, err := mac.LintEntitlements(*params)
+		if err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			return errors.New("no entitlements files are configured")
+		}
+		hasErrors := false
+		for _, report := range reports {
+			fmt.Print(report.String())
+			hasErrors = hasErrors || report.HasErrors()
+		}
+		if hasErrors {
+			return errors.New("the entitlements have errors")
+		}
+		return nil
+	},
+}
+
+func init() {
+	entitlementsCmd.Flags().StringVarP(&jsonPath, jsonPathFlag, "p", "",
+		`absolute path to the JSON file with the custom branding parameters`)
+	rootCmd.AddCommand(entitlementsCmd)
+}
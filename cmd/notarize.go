@@ -0,0 +1,116 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
+	"github.com/spf13/cobra"
+)
+
+var notarizeCmd = &cobra.Command{
+	Use:   `notarize`,
+	Short: `Notarizes the branded macOS app bundle in separate steps`,
+	Long: `Notarizes the branded macOS app bundle in separate steps: "submit" uploads the app bundle and saves
+the submission ID in the output directory, "status" prints the status of the submission, and "finalize"
+waits for the result, verifies the app bundle, and staples the ticket to it`,
+}
+
+var notarizeSubmitCmd = &cobra.Command{
+	Use:   `submit`,
+	Short: `Submits the app bundle for notarization without waiting for the result`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := getNotarizeParams(cmd)
+		if err != nil {
+			return err
+		}
+		id, err := mac.SubmitNotarization(outputDirPath, *params)
+		if err != nil {
+			return fmt.Errorf("failed to submit the app bundle: %w", err)
+		}
+		fmt.Println(id)
+		return nil
+	},
+}
+
+var notarizeStatusCmd = &cobra.Command{
+	Use:   `status`,
+	Short: `Prints the status of the submitted app bundle`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := getNotarizeParams(cmd)
+		if err != nil {
+			return err
+		}
+		id, status, err := mac.NotarizationStatus(outputDirPath, *params)
+		if err != nil {
+			return fmt.Errorf("failed to get the notarization status: %w", err)
+		}
+		fmt.Println(id + ": " + status)
+		return nil
+	},
+}
+
+var notarizeFinalizeCmd = &cobra.Command{
+	Use:   `finalize`,
+	Short: `Waits for the notarization result, verifies the app bundle, and staples the ticket to it`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := getNotarizeParams(cmd)
+		if err != nil {
+			return err
+		}
+		if err := mac.FinalizeNotarization(outputDirPath, *params); err != nil {
+			return fmt.Errorf("failed to finalize notarization: %w", err)
+		}
+		return nil
+	},
+}
+
+// getNotarizeParams checks the flags of the notarize subcommands
+// and obtains the branding parameters.
+func getNotarizeParams(cmd *cobra.Command) (*common.BrandingParams, error) {
+	if !cmd.Flags().Changed(jsonPathFlag) {
+		return nil, errors.New("missing flag: " + jsonPathFlag)
+	}
+	if !cmd.Flags().Changed(outputBinariesDirFlag) {
+		return nil, errors.New("missing flag: " + outputBinariesDirFlag)
+	}
+	params, err := common.GetBrandingParams(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not obtain branding info: %w", err)
+	}
+	base.Verbose = verbose
+	return params, nil
+}
+
+func init() {
+	notarizeCmd.PersistentFlags().StringVarP(&jsonPath, jsonPathFlag, "p", "",
+		`absolute path to the JSON file with the custom branding parameters`)
+	notarizeCmd.PersistentFlags().StringVarP(&outputDirPath, outputBinariesDirFlag, "o", "",
+		`absolute path to the directory with the branded and signed Chromium binaries`)
+	notarizeCmd.PersistentFlags().BoolVarP(&verbose, verboseFlag, "v", false,
+		`enable verbose output`)
+	notarizeCmd.AddCommand(notarizeSubmitCmd, notarizeStatusCmd, notarizeFinalizeCmd)
+	rootCmd.AddCommand(notarizeCmd)
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/core"
-	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
 	"github.com/spf13/cobra"
 )
 
@@ -68,19 +67,24 @@ var rootCmd = &cobra.Command{
 		// Set the verbose flag for logging and command line output.
 		base.Verbose = verbose
 
-		if err := core.BrandBinaries(*params, binariesDir, outputDirPath); err != nil {
-			return fmt.Errorf("failed to brand Chromium binaries: %w", err)
+		// Fail before branding if a required release step cannot be done.
+		if err := core.CheckPolicies(outputDirPath, *params); err != nil {
+			return err
 		}
 
-		if _, err := core.SignAppBinaries(outputDirPath, *params); err != nil {
-			return err
+		if err := core.BrandBinaries(*params, binariesDir, outputDirPath); err != nil {
+			return fmt.Errorf("failed to brand Chromium binaries: %w", err)
 		}
 
-		if _, err = mac.Notarize(outputDirPath, *params); err != nil {
+		signing, err := core.SignApp(outputDirPath, *params)
+		if err != nil {
+			core.PrintReleaseSummary(signing, core.ReleaseStatus{Reason: "not started"})
 			return err
 		}
 
-		return nil
+		notarization, err := core.NotarizeApp(outputDirPath, *params, signing)
+		core.PrintReleaseSummary(signing, notarization)
+		return err
 	},
 }
 
@@ -5,12 +5,14 @@ go 1.20
 require (
 	github.com/otiai10/copy v1.14.1
 	github.com/spf13/cobra v1.8.1
+	golang.org/x/sys v0.24.0
+	software.sslmate.com/src/go-pkcs12 v0.5.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/otiai10/mint v1.6.3 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/crypto v0.11.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
-	golang.org/x/sys v0.24.0 // indirect
 )
@@ -0,0 +1,532 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package base
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	// Register the hash functions used by the signatures.
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var (
+	oidSignedData     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidTimeStampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+	oidMSTimeStamp    = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 3, 3, 1}
+	oidTSTInfo        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+
+	digestAlgorithms = map[string]crypto.Hash{
+		"1.3.14.3.2.26":          crypto.SHA1,
+		"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+		"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+		"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+	}
+)
+
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type cmsSignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo cmsEncapContentInfo
+	Certificates     asn1.RawValue   `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue   `asn1:"optional,tag:1"`
+	SignerInfos      []cmsSignerInfo `asn1:"set"`
+}
+
+type cmsEncapContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type cmsSignerInfo struct {
+	Version            int
+	SignerIdentifier   asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type cmsIssuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type cmsAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// SignedData is a parsed CMS (PKCS #7) SignedData structure with one signer,
+// as used by Authenticode signatures, code signatures of Mach-O files, and
+// provisioning profiles.
+type SignedData struct {
+	// ContentType is the type of the signed content.
+	ContentType asn1.ObjectIdentifier
+
+	// Content is the signed content without its outer tag and length.
+	Content []byte
+
+	// Certificates are the certificates embedded in the structure.
+	Certificates []*x509.Certificate
+
+	// Signer is the certificate of the signer, found among Certificates.
+	Signer *x509.Certificate
+
+	signerInfo cmsSignerInfo
+}
+
+// ParseSignedData parses a DER-encoded CMS ContentInfo holding SignedData.
+func ParseSignedData(der []byte) (*SignedData, error) {
+	var info cmsContentInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid CMS content info: %w", err)
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("unexpected CMS content type %s", info.ContentType)
+	}
+	var signedData cmsSignedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &signedData); err != nil {
+		return nil, fmt.Errorf("invalid CMS signed data: %w", err)
+	}
+	if len(signedData.SignerInfos) != 1 {
+		return nil, fmt.Errorf("expected one signer, found %d", len(signedData.SignerInfos))
+	}
+
+	result := &SignedData{
+		ContentType: signedData.EncapContentInfo.ContentType,
+		signerInfo:  signedData.SignerInfos[0],
+	}
+	if content := signedData.EncapContentInfo.Content.Bytes; len(content) != 0 {
+		var inner asn1.RawValue
+		if _, err := asn1.Unmarshal(content, &inner); err != nil {
+			return nil, fmt.Errorf("invalid CMS content: %w", err)
+		}
+		result.Content = inner.Bytes
+	}
+	if len(signedData.Certificates.Bytes) != 0 {
+		certificates, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CMS certificates: %w", err)
+		}
+		result.Certificates = certificates
+	}
+
+	signer, err := result.findCertificate(result.signerInfo.SignerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	result.Signer = signer
+	return result, nil
+}
+
+// findCertificate returns the certificate identified by the issuer and
+// the serial number, or by the subject key identifier.
+func (signedData *SignedData) findCertificate(id asn1.RawValue) (*x509.Certificate, error) {
+	if id.Class == asn1.ClassContextSpecific && id.Tag == 0 {
+		for _, certificate := range signedData.Certificates {
+			if bytes.Equal(certificate.SubjectKeyId, id.Bytes) {
+				return certificate, nil
+			}
+		}
+		return nil, errors.New("the signer certificate is missing")
+	}
+	var issuerAndSerial cmsIssuerAndSerial
+	if _, err := asn1.Unmarshal(id.FullBytes, &issuerAndSerial); err != nil {
+		return nil, fmt.Errorf("invalid CMS signer identifier: %w", err)
+	}
+	for _, certificate := range signedData.Certificates {
+		if bytes.Equal(certificate.RawIssuer, issuerAndSerial.Issuer.FullBytes) &&
+			certificate.SerialNumber.Cmp(issuerAndSerial.Serial) == 0 {
+			return certificate, nil
+		}
+	}
+	return nil, errors.New("the signer certificate is missing")
+}
+
+// DigestAlgorithm returns the hash function the signer used.
+func (signedData *SignedData) DigestAlgorithm() (crypto.Hash, error) {
+	return digestAlgorithm(signedData.signerInfo.DigestAlgorithm)
+}
+
+// VerifySignature checks that the signer has signed the content.
+//
+// It does not check the certificate chain of the signer.
+func (signedData *SignedData) VerifySignature() error {
+	info := signedData.signerInfo
+	hash, err := digestAlgorithm(info.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	signed := signedData.Content
+	if len(info.SignedAttributes.FullBytes) != 0 {
+		digest, err := signedData.attributeValue(info.SignedAttributes, oidMessageDigest)
+		if err != nil {
+			return err
+		}
+		var expected []byte
+		if _, err := asn1.Unmarshal(digest, &expected); err != nil {
+			return fmt.Errorf("invalid message digest: %w", err)
+		}
+		h := hash.New()
+		h.Write(signedData.Content)
+		if !bytes.Equal(h.Sum(nil), expected) {
+			return errors.New("the message digest does not match the content")
+		}
+		// The signature covers the DER encoding of the attributes as a SET.
+		signed = append([]byte{0x31}, info.SignedAttributes.FullBytes[1:]...)
+	}
+
+	if err := verifySignatureValue(signedData.Signer, hash, signed, info.Signature); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyChain checks that the signer certificate chains up to a trusted root
+// for code signing at the signing time: the time of the timestamp if the
+// signature has a valid one, or the current time otherwise.
+func (signedData *SignedData) VerifyChain(roots *x509.CertPool) error {
+	_, err := signedData.Signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: signedData.intermediates(),
+		CurrentTime:   signedData.SigningTime(roots),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	return err
+}
+
+// SigningTime returns the time of the timestamp if the signature has one
+// that is valid and chains up to the roots, or the current time otherwise.
+func (signedData *SignedData) SigningTime(roots *x509.CertPool) time.Time {
+	info := signedData.signerInfo
+	if len(info.UnsignedAttributes.FullBytes) != 0 {
+		for _, oid := range []asn1.ObjectIdentifier{oidTimeStampToken, oidMSTimeStamp} {
+			if token, err := signedData.attributeValue(info.UnsignedAttributes, oid); err == nil {
+				if stamp, err := timeStampTime(token, info.Signature, roots); err == nil {
+					return stamp
+				}
+			}
+		}
+	}
+	return time.Now()
+}
+
+// intermediates returns a pool of the certificates embedded in the structure.
+func (signedData *SignedData) intermediates() *x509.CertPool {
+	intermediates := x509.NewCertPool()
+	for _, certificate := range signedData.Certificates {
+		intermediates.AddCert(certificate)
+	}
+	return intermediates
+}
+
+// attributeValue returns the DER encoding of the first value of
+// the attribute with the given type.
+func (signedData *SignedData) attributeValue(attributes asn1.RawValue, oid asn1.ObjectIdentifier) ([]byte, error) {
+	rest := attributes.Bytes
+	for len(rest) > 0 {
+		var attribute cmsAttribute
+		var err error
+		rest, err = asn1.Unmarshal(rest, &attribute)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CMS attribute: %w", err)
+		}
+		if attribute.Type.Equal(oid) {
+			var value asn1.RawValue
+			if _, err := asn1.Unmarshal(attribute.Values.Bytes, &value); err != nil {
+				return nil, fmt.Errorf("invalid CMS attribute %s: %w", oid, err)
+			}
+			return value.FullBytes, nil
+		}
+	}
+	return nil, fmt.Errorf("no CMS attribute %s", oid)
+}
+
+// timeStampTime returns the time of an RFC 3161 timestamp token after checking
+// that the token is signed by a timestamping authority chaining up to the roots,
+// and that it stamps the signature.
+func timeStampTime(token, signature []byte, roots *x509.CertPool) (time.Time, error) {
+	signedData, err := ParseSignedData(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !signedData.ContentType.Equal(oidTSTInfo) {
+		return time.Time{}, fmt.Errorf("unexpected timestamp content type %s", signedData.ContentType)
+	}
+	if err := signedData.VerifySignature(); err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	var info struct {
+		Version        int
+		Policy         asn1.ObjectIdentifier
+		MessageImprint struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}
+		SerialNumber *big.Int
+		GenTime      time.Time `asn1:"generalized"`
+	}
+	if _, err := asn1.Unmarshal(signedData.Content, &info); err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	hash, err := digestAlgorithm(info.MessageImprint.HashAlgorithm)
+	if err != nil {
+		return time.Time{}, err
+	}
+	h := hash.New()
+	h.Write(signature)
+	if !bytes.Equal(h.Sum(nil), info.MessageImprint.HashedMessage) {
+		return time.Time{}, errors.New("the timestamp does not match the signature")
+	}
+	if _, err := signedData.Signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: signedData.intermediates(),
+		CurrentTime:   info.GenTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}); err != nil {
+		return time.Time{}, fmt.Errorf("untrusted timestamp: %w", err)
+	}
+	return info.GenTime, nil
+}
+
+func digestAlgorithm(algorithm pkix.AlgorithmIdentifier) (crypto.Hash, error) {
+	hash, ok := digestAlgorithms[algorithm.Algorithm.String()]
+	if !ok {
+		return 0, fmt.Errorf("unsupported digest algorithm %s", algorithm.Algorithm)
+	}
+	return hash, nil
+}
+
+// verifySignatureValue checks the signature of the hash of signed with
+// the public key of the certificate. Unlike x509.Certificate.CheckSignature,
+// it accepts SHA-1, still used by older Authenticode signatures.
+func verifySignatureValue(certificate *x509.Certificate, hash crypto.Hash, signed, signature []byte) error {
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	switch key := certificate.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, signature) {
+			return errors.New("ECDSA verification failure")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA  = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA256: {1, 2, 840, 10045, 4, 3, 2},
+		crypto.SHA384: {1, 2, 840, 10045, 4, 3, 3},
+		crypto.SHA512: {1, 2, 840, 10045, 4, 3, 4},
+	}
+	digestAlgorithmOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA1:   {1, 3, 14, 3, 2, 26},
+		crypto.SHA256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+		crypto.SHA384: {2, 16, 840, 1, 101, 3, 4, 2, 2},
+		crypto.SHA512: {2, 16, 840, 1, 101, 3, 4, 2, 3},
+	}
+)
+
+// CMSAttribute is an additional signed attribute of a CMS signature.
+type CMSAttribute struct {
+	Type asn1.ObjectIdentifier
+
+	// Value is the DER encoding of the single value of the attribute.
+	Value []byte
+}
+
+// CMSSigner holds what is needed to create a CMS signature.
+type CMSSigner struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+
+	// Chain are the certificates embedded besides the signer one.
+	Chain []*x509.Certificate
+
+	// Hash is the digest algorithm, SHA-256 if not set.
+	Hash crypto.Hash
+
+	// TimestampURL is the URL of the RFC 3161 timestamp server. The signature
+	// is not timestamped if empty.
+	TimestampURL string
+}
+
+// SignDetached creates a DER-encoded CMS ContentInfo with SignedData that signs
+// the content without embedding it.
+func (signer CMSSigner) SignDetached(content []byte, attributes []CMSAttribute) ([]byte, error) {
+	hash := signer.Hash
+	if hash == 0 {
+		hash = crypto.SHA256
+	}
+	digestOID, ok := digestAlgorithmOIDs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported digest algorithm %s", hash)
+	}
+	digestAlgorithm := pkix.AlgorithmIdentifier{Algorithm: digestOID, Parameters: asn1.NullRawValue}
+
+	h := hash.New()
+	h.Write(content)
+	contentTypeValue, err := asn1.Marshal(oidData)
+	if err != nil {
+		return nil, err
+	}
+	signingTimeValue, err := asn1.Marshal(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	messageDigestValue, err := asn1.Marshal(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	attributes = append([]CMSAttribute{
+		{Type: oidContentType, Value: contentTypeValue},
+		{Type: oidSigningTime, Value: signingTimeValue},
+		{Type: oidMessageDigest, Value: messageDigestValue},
+	}, attributes...)
+	signedAttributes, err := encodeAttributes(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature covers the attributes encoded as a SET.
+	h = hash.New()
+	h.Write(append([]byte{0x31}, signedAttributes[1:]...))
+	signature, err := signer.Key.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign: %w", err)
+	}
+
+	var signatureAlgorithm pkix.AlgorithmIdentifier
+	switch signer.Key.Public().(type) {
+	case *rsa.PublicKey:
+		signatureAlgorithm = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+	case *ecdsa.PublicKey:
+		oid, ok := oidECDSAWithSHA[hash]
+		if !ok {
+			return nil, fmt.Errorf("unsupported ECDSA digest algorithm %s", hash)
+		}
+		signatureAlgorithm = pkix.AlgorithmIdentifier{Algorithm: oid}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", signer.Key.Public())
+	}
+
+	info := cmsSignerInfo{
+		Version:            1,
+		DigestAlgorithm:    digestAlgorithm,
+		SignedAttributes:   asn1.RawValue{FullBytes: signedAttributes},
+		SignatureAlgorithm: signatureAlgorithm,
+		Signature:          signature,
+	}
+	info.SignerIdentifier.FullBytes, err = asn1.Marshal(cmsIssuerAndSerial{
+		Issuer: asn1.RawValue{FullBytes: signer.Certificate.RawIssuer},
+		Serial: signer.Certificate.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.TimestampURL != "" {
+		token, err := RequestTimestamp(signer.TimestampURL, signature, hash)
+		if err != nil {
+			return nil, err
+		}
+		unsignedAttributes, err := encodeAttributes([]CMSAttribute{{Type: oidTimeStampToken, Value: token}})
+		if err != nil {
+			return nil, err
+		}
+		unsignedAttributes[0] = 0xA1
+		info.UnsignedAttributes = asn1.RawValue{FullBytes: unsignedAttributes}
+	}
+
+	certificates := []byte{}
+	for _, certificate := range append([]*x509.Certificate{signer.Certificate}, signer.Chain...) {
+		certificates = append(certificates, certificate.Raw...)
+	}
+	digestAlgorithms, err := asn1.Marshal(digestAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := asn1.Marshal(cmsSignedData{
+		Version:          1,
+		DigestAlgorithms: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: digestAlgorithms},
+		EncapContentInfo: cmsEncapContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos:      []cmsSignerInfo{info},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(cmsContentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// encodeAttributes encodes the attributes as an IMPLICIT [0] SET sorted as DER requires.
+func encodeAttributes(attributes []CMSAttribute) ([]byte, error) {
+	encoded := [][]byte{}
+	for _, attribute := range attributes {
+		value, err := asn1.Marshal(struct {
+			Type   asn1.ObjectIdentifier
+			Values asn1.RawValue
+		}{
+			Type:   attribute.Type,
+			Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attribute.Value},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, value)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: bytes.Join(encoded, nil)})
+}
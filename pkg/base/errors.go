@@ -20,6 +20,35 @@
 
 package base
 
+import "strings"
+
+// CommandError is returned when a command fails. It carries the combined
+// standard and error output of the command, so that the callers can report
+// it or decide whether to retry the command.
+type CommandError struct {
+	// Command is the name of the program that failed. The arguments are
+	// left out, since they may contain passwords.
+	Command string
+
+	// Output is the combined output of the command.
+	Output string
+
+	// Err is the error of starting or waiting for the command.
+	Err error
+}
+
+func (err *CommandError) Error() string {
+	message := err.Command + " failed: " + err.Err.Error()
+	if output := strings.TrimSpace(err.Output); output != "" {
+		message += "\n" + output
+	}
+	return message
+}
+
+func (err *CommandError) Unwrap() error {
+	return err.Err
+}
+
 // AnyErrorFrom returns the first non-nil error from the given list of errors, or nil if none are non-nil.
 func AnyErrorFrom(errs ...error) error {
 	for _, err := range errs {
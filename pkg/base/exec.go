@@ -22,11 +22,13 @@ package base
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"strings"
+	"unicode"
 )
 
 // Exec executes the given command in the current working directory.
@@ -85,11 +87,85 @@ func ExecCommandInWorkingDir(command string, args []string, workingDir string, e
 	cmd.Stderr = mw
 
 	if err := cmd.Start(); err != nil {
-		return stdBuffer.Bytes(), err
+		return stdBuffer.Bytes(), &CommandError{Command: programName(command, args), Output: stdBuffer.String(), Err: err}
 	}
 
 	if err := cmd.Wait(); err != nil {
-		return stdBuffer.Bytes(), err
+		return stdBuffer.Bytes(), &CommandError{Command: programName(command, args), Output: stdBuffer.String(), Err: err}
 	}
 	return stdBuffer.Bytes(), nil
 }
+
+// programName returns the file name of the program the command runs. Without
+// the arguments, the command is a command line starting with the program path,
+// which is quoted if it contains spaces.
+func programName(command string, args []string) string {
+	program := strings.TrimSpace(command)
+	if len(args) == 0 {
+		if strings.HasPrefix(program, `"`) {
+			program = strings.SplitN(program[1:], `"`, 2)[0]
+		} else if fields := strings.Fields(program); len(fields) != 0 {
+			program = fields[0]
+		}
+	}
+	return program[strings.LastIndexAny(program, `/\`)+1:]
+}
+
+// SplitCommandLine splits the command line into the program and its arguments
+// as a POSIX shell does, but without expansions. Single quotes keep the text
+// as is, double quotes keep the text except for the escaped \" and \\, and
+// a backslash outside quotes escapes the next character.
+func SplitCommandLine(commandLine string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	runes := []rune(commandLine)
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+		switch {
+		case char == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated single quote in the command: " + commandLine)
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			inArg = true
+			i = end
+		case char == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				current.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, errors.New("unterminated double quote in the command: " + commandLine)
+			}
+			inArg = true
+		case char == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case unicode.IsSpace(char):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(char)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("the command is empty")
+	}
+	return args, nil
+}
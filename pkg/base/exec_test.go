@@ -0,0 +1,74 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package base
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProgramNameOmitsArguments(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+	}{
+		{`"C:\Program Files (x86)\Windows Kits\10\bin\x64\signtool.exe" sign /p secret /tr http://timestamp.example`, nil, "signtool.exe"},
+		{`C:\tools\signtool.exe sign /p secret`, nil, "signtool.exe"},
+		{"/usr/bin/codesign", []string{"--sign", "Developer ID"}, "codesign"},
+	}
+	for _, test := range tests {
+		if got := programName(test.command, test.args); got != test.want {
+			t.Errorf("programName(%q) = %q, want %q", test.command, got, test.want)
+		}
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "signtool sign /a  file.exe", want: []string{"signtool", "sign", "/a", "file.exe"}},
+		{line: `signtool sign "C:\Program Files\App\app.exe"`, want: []string{"signtool", "sign", `C:\Program Files\App\app.exe`}},
+		{line: `echo "a \"quoted\" word" 'single "quotes"'`, want: []string{"echo", `a "quoted" word`, `single "quotes"`}},
+		{line: `file\ name.exe --flag=a"b c"`, want: []string{"file name.exe", "--flag=ab c"}},
+		{line: `sign ""`, want: []string{"sign", ""}},
+		{line: `sign "unterminated`, wantErr: true},
+		{line: "sign 'unterminated", wantErr: true},
+		{line: " \t ", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := SplitCommandLine(test.line)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("SplitCommandLine(%q) = %q, want an error", test.line, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitCommandLine(%q) failed: %v", test.line, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
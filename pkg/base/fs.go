@@ -297,6 +297,26 @@ func CopyFile(sourcePath, destPath string) error {
 	return nil
 }
 
+// WriteTempFile creates a new temporary file with the name matching pattern
+// (see os.CreateTemp) and writes data into it. Returns the path to the file;
+// the caller is responsible for removing it.
+func WriteTempFile(pattern string, data []byte) (string, error) {
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	return tmpFile.Name(), nil
+}
+
 func renameLastPathEntry(entryPath AbsPath, newName string) (AbsPath, error) {
 	if newName == entryPath.Base() {
 		return entryPath, nil
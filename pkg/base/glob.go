@@ -0,0 +1,63 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package base
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash-separated relative path matches the glob,
+// ignoring the case.
+//
+// A glob without a slash is matched against the base name of the path.
+// Otherwise, it is matched against the whole path, and a `**` segment
+// matches any number of directories.
+func MatchGlob(glob, relPath string) bool {
+	glob = strings.ToLower(glob)
+	relPath = strings.ToLower(relPath)
+	if !strings.Contains(glob, "/") {
+		matched, _ := path.Match(glob, path.Base(relPath))
+		return matched
+	}
+	return matchGlobSegments(strings.Split(glob, "/"), strings.Split(relPath, "/"))
+}
+
+func matchGlobSegments(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(glob[0], segments[0]); !matched {
+		return false
+	}
+	return matchGlobSegments(glob[1:], segments[1:])
+}
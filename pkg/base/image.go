@@ -0,0 +1,181 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package base
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+)
+
+// lanczosRadius is the number of lobes of the Lanczos filter used by ResizeImage.
+const lanczosRadius = 3
+
+// DecodePNGFile reads and decodes the PNG image located at path.
+func DecodePNGFile(path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// EncodePNG encodes the image in the PNG format.
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// ResizeImage scales the image to width x height pixels using the Lanczos-3
+// filter. The filter is applied to the premultiplied colors, so that
+// the transparent pixels do not bleed into the edges of the icon.
+func ResizeImage(img image.Image, width, height int) *image.NRGBA {
+	src := premultipliedPixels(img)
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	// Scale the rows first, then the columns of the intermediate image.
+	rows := make([]float64, srcHeight*width*4)
+	columnWeights := lanczosWeights(srcWidth, width)
+	for y := 0; y < srcHeight; y++ {
+		for x, weights := range columnWeights {
+			for c := 0; c < 4; c++ {
+				sum := 0.0
+				for _, w := range weights {
+					sum += w.weight * src[(y*srcWidth+w.index)*4+c]
+				}
+				rows[(y*width+x)*4+c] = sum
+			}
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rowWeights := lanczosWeights(srcHeight, height)
+	for y, weights := range rowWeights {
+		for x := 0; x < width; x++ {
+			var pixel [4]float64
+			for c := 0; c < 4; c++ {
+				for _, w := range weights {
+					pixel[c] += w.weight * rows[(w.index*width+x)*4+c]
+				}
+			}
+			dst.SetNRGBA(x, y, unpremultiply(pixel))
+		}
+	}
+	return dst
+}
+
+// resampleWeight is the contribution of a source pixel to a destination pixel.
+type resampleWeight struct {
+	index  int
+	weight float64
+}
+
+// lanczosWeights returns the normalized filter weights of the source pixels
+// for every destination pixel when scaling srcSize pixels to dstSize pixels.
+func lanczosWeights(srcSize, dstSize int) [][]resampleWeight {
+	scale := float64(srcSize) / float64(dstSize)
+	// When downscaling, the filter is stretched to cover all the source pixels.
+	filterScale := math.Max(scale, 1)
+	support := lanczosRadius * filterScale
+
+	weights := make([][]resampleWeight, dstSize)
+	for i := range weights {
+		center := (float64(i)+0.5)*scale - 0.5
+		start := int(math.Floor(center - support))
+		end := int(math.Ceil(center + support))
+
+		total := 0.0
+		for j := start; j <= end; j++ {
+			weight := lanczos((float64(j) - center) / filterScale)
+			if weight == 0 {
+				continue
+			}
+			index := j
+			if index < 0 {
+				index = 0
+			} else if index >= srcSize {
+				index = srcSize - 1
+			}
+			weights[i] = append(weights[i], resampleWeight{index: index, weight: weight})
+			total += weight
+		}
+		for j := range weights[i] {
+			weights[i][j].weight /= total
+		}
+	}
+	return weights
+}
+
+// lanczos evaluates the Lanczos kernel at x.
+func lanczos(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	if x <= -lanczosRadius || x >= lanczosRadius {
+		return 0
+	}
+	px := math.Pi * x
+	return lanczosRadius * math.Sin(px) * math.Sin(px/lanczosRadius) / (px * px)
+}
+
+// premultipliedPixels returns the pixels of the image as premultiplied
+// RGBA values in the [0, 1] range, row by row.
+func premultipliedPixels(img image.Image) []float64 {
+	bounds := img.Bounds()
+	pixels := make([]float64, 0, bounds.Dx()*bounds.Dy()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			pixels = append(pixels,
+				float64(r)/0xFFFF, float64(g)/0xFFFF, float64(b)/0xFFFF, float64(a)/0xFFFF)
+		}
+	}
+	return pixels
+}
+
+// unpremultiply converts the premultiplied RGBA value in the [0, 1] range
+// to the 8-bit non-premultiplied color, clamping the filter overshoot.
+func unpremultiply(pixel [4]float64) color.NRGBA {
+	alpha := clampUnit(pixel[3])
+	if alpha == 0 {
+		return color.NRGBA{}
+	}
+	channel := func(value float64) uint8 {
+		return uint8(math.Round(clampUnit(value/alpha) * 0xFF))
+	}
+	return color.NRGBA{
+		R: channel(pixel[0]),
+		G: channel(pixel[1]),
+		B: channel(pixel[2]),
+		A: uint8(math.Round(alpha * 0xFF)),
+	}
+}
+
+func clampUnit(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package base
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+type timeStampRequest struct {
+	Version        int
+	MessageImprint messageImprint
+	Nonce          *big.Int
+	CertReq        bool
+}
+
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+type timeStampResponse struct {
+	Status struct {
+		Status       int
+		StatusString asn1.RawValue  `asn1:"optional"`
+		FailInfo     asn1.BitString `asn1:"optional"`
+	}
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// timestampClient is the HTTP client used to request timestamps.
+var timestampClient = &http.Client{Timeout: 60 * time.Second}
+
+// RequestTimestamp requests an RFC 3161 timestamp of the data from the server
+// and returns the DER-encoded timestamp token.
+func RequestTimestamp(url string, data []byte, hash crypto.Hash) ([]byte, error) {
+	oid, ok := digestAlgorithmOIDs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported digest algorithm %s", hash)
+	}
+	h := hash.New()
+	h.Write(data)
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	request, err := asn1.Marshal(timeStampRequest{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.NullRawValue},
+			HashedMessage: h.Sum(nil),
+		},
+		Nonce:   nonce,
+		CertReq: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := timestampClient.Post(url, "application/timestamp-query", bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("timestamp server %s: %w", url, err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("timestamp server %s: %w", url, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("timestamp server %s: %s", url, response.Status)
+	}
+
+	var parsed timeStampResponse
+	if _, err := asn1.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("timestamp server %s: invalid response: %w", url, err)
+	}
+	// 0 is granted, 1 is granted with modifications.
+	if parsed.Status.Status > 1 || len(parsed.TimeStampToken.FullBytes) == 0 {
+		return nil, fmt.Errorf("timestamp server %s: the request is rejected with status %d", url, parsed.Status.Status)
+	}
+	return parsed.TimeStampToken.FullBytes, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// BrandFile selects the Windows binaries to stamp with the version information
+// and overrides the values for them.
+//
+// In the JSON file, it can be specified either as a glob, or as an object
+// with the glob and the overrides:
+//
+//	"brandFiles": [
+//	  "*.exe",
+//	  "*.dll",
+//	  "!libEGL.dll",
+//	  {"glob": "notification_helper.exe", "versionStrings": {"FileDescription": "My App Notifications"}}
+//	]
+//
+// A glob starting with "!" excludes the matching files. A glob without a slash
+// matches the file names in all the directories, otherwise it matches the path
+// relative to the binaries directory.
+type BrandFile struct {
+	Glob string
+
+	// Author overrides Win.Author for the matching files.
+	Author *string
+
+	// ProductName overrides Win.ProductName for the matching files.
+	ProductName *string
+
+	// LegalCopyright overrides Win.LegalCopyright for the matching files.
+	LegalCopyright *string
+
+	// Version overrides BrandingParams.Version for the matching files.
+	Version *Version
+
+	// VersionStrings sets the arbitrary version strings of the matching files.
+	VersionStrings map[string]string
+}
+
+// UnmarshalJSON accepts either a glob or a brand file object.
+func (brandFile *BrandFile) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &brandFile.Glob)
+	}
+
+	// The alias type prevents the infinite recursion into this method.
+	type brandFileObject BrandFile
+	var object brandFileObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*brandFile = BrandFile(object)
+	return nil
+}
@@ -33,6 +33,16 @@ type Win struct {
 	// for the Windows executable.
 	IcoPath *string
 
+	// IconsetPath is a path to a directory with the PNG images named
+	// Icon_<size>x<size>.png. The .ico file is built from these images
+	// if IcoPath is not set.
+	IconsetPath *string
+
+	// Icons maps the ids of the icon groups (RT_GROUP_ICON resources) in
+	// the executable and chrome.dll to the paths to the .ico files embedded
+	// into them. The "default" key sets the icon for all the other groups.
+	Icons map[string]string
+
 	// ExecutableName is the name of the executable file.
 	ExecutableName *string
 
@@ -51,7 +61,60 @@ type Win struct {
 	// ProductName is a user-friendly name for the product.
 	ProductName *string
 
-	SignCommand string
+	// VersionStrings maps the keys of the StringFileInfo table of the
+	// executable (e.g., Comments, LegalTrademarks) to their values.
+	// These values override the ones set by the other parameters.
+	VersionStrings map[string]string
+
+	// LocalizedVersionStrings maps the languages, either LCIDs (e.g., 1041,
+	// 0x0411) or BCP-47 tags (e.g., ja-JP), to the version strings shown
+	// to the users of these languages.
+	LocalizedVersionStrings map[string]map[string]string
+
+	// BrandFiles selects the binaries other than the executable to stamp
+	// with the author, product name, copyright, and version. If not set,
+	// all the .exe and .dll files except the third-party runtimes are stamped.
+	BrandFiles []BrandFile
+
+	// Manifest describes the changes of the application manifest of the executable.
+	Manifest *Manifest
+
+	// ProductUrl is the URL of the product, available to SignCommand
+	// as the @@PRODUCT_URL@@ placeholder (e.g., for signtool /du).
+	ProductUrl *string
+
+	// SignCommand is the command signing the binaries. See SignCommand for details.
+	SignCommand *SignCommand
+
+	// SignBatchSize is the maximum number of files passed to a single
+	// sign command with the @@BINARY_PATHS@@ placeholder. If not set, it is
+	// unlimited, except for a command line run by cmd on Windows.
+	SignBatchSize *int
+}
+
+// Manifest holds the changes of the application manifest embedded
+// into the Windows executable.
+type Manifest struct {
+	// Path is a path to the XML file replacing the embedded manifest.
+	// The other changes apply on top of it.
+	Path *string
+
+	// AssemblyName is the name attribute of the assemblyIdentity element.
+	AssemblyName *string
+
+	// DpiAware is the value of the dpiAware element (e.g., true/pm).
+	DpiAware *string
+
+	// DpiAwareness is the value of the dpiAwareness element
+	// (e.g., PerMonitorV2, PerMonitor).
+	DpiAwareness *string
+
+	// LongPathAware is the value of the longPathAware element.
+	LongPathAware *bool
+
+	// RequestedExecutionLevel is the level attribute of the requestedExecutionLevel
+	// element: asInvoker, highestAvailable, or requireAdministrator.
+	RequestedExecutionLevel *string
 }
 
 // Bundle holds macOS-specific metadata about application bundles.
@@ -68,6 +131,11 @@ type Mac struct {
 	// IcnsPath is a path to an .icns file used as the application icon.
 	IcnsPath *string
 
+	// IconsetPath is a path to a directory with the PNG images named as in
+	// an Xcode .iconset directory (e.g., icon_16x16.png, icon_16x16@2x.png).
+	// The .icns file is built from these images if IcnsPath is not set.
+	IconsetPath *string
+
 	// Bundle contains metadata related to the macOS application bundle.
 	Bundle *Bundle
 
@@ -78,6 +146,61 @@ type Mac struct {
 	AppleId                 string
 	Password                string
 	InformationPropertyList string
+
+	// CodesignCertificate is a path to a PKCS #12 (.p12) file with
+	// the certificate and the private key to sign the app bundle with
+	// without codesign.
+	CodesignCertificate string
+
+	// CodesignCertificatePassword is the password of CodesignCertificate.
+	CodesignCertificatePassword string
+
+	// CodesignTool is "codesign" or "go". By default, the app bundle is
+	// signed in Go if CodesignCertificate is set or the tool does not run
+	// on macOS, and with codesign otherwise.
+	CodesignTool string
+
+	// TimestampUrl is the URL of the timestamp server used when signing
+	// in Go. Apple's server by default, "none" disables timestamping.
+	TimestampUrl string
+
+	// Entitlements maps the bundle types (main, helper, alerts, gpu, plugin,
+	// renderer) and "default" for the rest of the nested code to the entitlements
+	// files. CodesignEntitlements is used for the types not listed.
+	Entitlements map[string]string
+
+	// Notarization holds the credentials used to notarize the app bundle
+	// instead of AppleId, Password, and TeamId.
+	Notarization *Notarization
+}
+
+// Notarization holds the notarytool credentials: either an App Store Connect
+// API key or a keychain profile.
+type Notarization struct {
+	// ApiKey is a path to the App Store Connect API key (.p8 file).
+	ApiKey string
+
+	// ApiKeyId is the ID of ApiKey.
+	ApiKeyId string
+
+	// ApiIssuer is the issuer ID of ApiKey. Required for team API keys,
+	// and not set for individual API keys.
+	ApiIssuer string
+
+	// KeychainProfile is the name of the credentials stored in the keychain
+	// with `xcrun notarytool store-credentials`.
+	KeychainProfile string
+
+	// Tool is "api" to talk to the Notary REST API directly, or "notarytool".
+	// By default, the API is used if ApiKey is set.
+	Tool string
+
+	// ServiceUrl is the base URL of the Notary API. Apple's service by default.
+	ServiceUrl string
+
+	// UploadUrl is the URL of the S3-compatible service the archives are
+	// uploaded to. Amazon S3 by default.
+	UploadUrl string
 }
 
 // Linux holds Linux-specific branding parameters.
@@ -87,12 +210,65 @@ type Linux struct {
 	ExecutableName *string
 }
 
+// SigningOptions controls how the binaries are signed on Windows and macOS.
+type SigningOptions struct {
+	// Concurrency is the number of the files signed at the same time. 1 by default.
+	Concurrency *int
+
+	// Retries is the number of the retries of a failed sign command whose
+	// error matches RetryPatterns. 2 by default.
+	Retries *int
+
+	// RetryDelay is the delay in seconds before the first retry, doubled
+	// before every next retry. 5 by default.
+	RetryDelay *float64
+
+	// RetryPatterns are the regular expressions matched against the error
+	// and the output of a failed sign command. By default, they match
+	// the timestamp server and network errors.
+	RetryPatterns []string
+}
+
+// SigningManifest selects the files to sign among the binaries found in
+// the output directory.
+type SigningManifest struct {
+	// Include are the globs of the files to sign. All the found binaries
+	// are signed by default.
+	Include []string
+
+	// Exclude are the globs of the files not to sign, even if included.
+	Exclude []string
+
+	// SkipSignedBy are the publishers whose valid signatures are kept,
+	// e.g. "Microsoft Corporation". A publisher is matched against the common
+	// name and the organization of the certificate on Windows, and against
+	// the signing authority and the Team ID on macOS.
+	SkipSignedBy []string
+}
+
 // BrandingParams holds versioning and platform-specific branding
 // details used to customize executables and app bundles across
 // different operating systems.
 type BrandingParams struct {
-	// Version specifies the version string (e.g., "1.0.0").
-	Version *string
+	// Version specifies the version of the app. See Version for details.
+	Version *Version
+
+	// Icon is the master icon from which the icons of all the platforms
+	// are generated unless the platform-specific icons are set.
+	Icon *Icon
+
+	// Signing tells whether the binaries must be signed. Optional by default.
+	Signing Policy
+
+	// Notarization tells whether the macOS app bundle must be notarized.
+	// Optional by default.
+	Notarization Policy
+
+	// SigningOptions controls the concurrency and the retries of signing.
+	SigningOptions *SigningOptions
+
+	// SigningManifest selects the files to sign.
+	SigningManifest *SigningManifest
 
 	Win   Win
 	Mac   Mac
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+// Icon holds the master application icon from which the icons of all
+// the platforms are generated.
+//
+// In the JSON file, it can be specified either as a path to the master
+// PNG image, or as an object with the hand-tuned images for some sizes:
+//
+//	"icon": {
+//	  "path": "assets/icon.png",
+//	  "overrides": {
+//	    "16": "assets/icon_16.png",
+//	    "32": "assets/icon_32.png"
+//	  }
+//	}
+type Icon struct {
+	// Path is the path to the square master PNG image, preferably 1024x1024 pixels.
+	Path string
+
+	// Overrides maps an image size in pixels to the path to a PNG image
+	// used instead of the downscaled master image of that size.
+	Overrides map[int]string
+
+	// master caches the decoded master image.
+	master image.Image
+}
+
+// UnmarshalJSON accepts either a path to the master image or an icon object.
+func (icon *Icon) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &icon.Path)
+	}
+
+	// The alias type prevents the infinite recursion into this method.
+	type iconObject Icon
+	var object iconObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*icon = Icon(object)
+	return nil
+}
+
+// Image returns the size x size icon image. It is either the override
+// image for this size, or the master image scaled to this size.
+func (icon *Icon) Image(size int) (image.Image, error) {
+	if overridePath, ok := icon.Overrides[size]; ok {
+		img, err := base.DecodePNGFile(overridePath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the %dx%d icon %s: %w", size, size, overridePath, err)
+		}
+		if bounds := img.Bounds(); bounds.Dx() != size || bounds.Dy() != size {
+			return nil, fmt.Errorf("the %dx%d icon %s has %dx%d pixels", size, size, overridePath, bounds.Dx(), bounds.Dy())
+		}
+		return img, nil
+	}
+
+	master, err := icon.masterImage()
+	if err != nil {
+		return nil, err
+	}
+	if master.Bounds().Dx() == size {
+		return master, nil
+	}
+	if master.Bounds().Dx() < size {
+		fmt.Printf("WARNING: the master icon %s is smaller than %dx%d pixels and will be upscaled.\n", icon.Path, size, size)
+	}
+	return base.ResizeImage(master, size, size), nil
+}
+
+// PNG returns the size x size icon image encoded in the PNG format.
+func (icon *Icon) PNG(size int) ([]byte, error) {
+	img, err := icon.Image(size)
+	if err != nil {
+		return nil, err
+	}
+	return base.EncodePNG(img)
+}
+
+func (icon *Icon) masterImage() (image.Image, error) {
+	if icon.master != nil {
+		return icon.master, nil
+	}
+	if icon.Path == "" {
+		return nil, fmt.Errorf("the master icon path is empty")
+	}
+
+	master, err := base.DecodePNGFile(icon.Path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the master icon %s: %w", icon.Path, err)
+	}
+	if bounds := master.Bounds(); bounds.Dx() != bounds.Dy() {
+		return nil, fmt.Errorf("the master icon %s is not square (%dx%d pixels)", icon.Path, bounds.Dx(), bounds.Dy())
+	}
+	icon.master = master
+	return master, nil
+}
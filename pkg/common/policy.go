@@ -0,0 +1,66 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Policy tells whether a release step, such as signing or notarization,
+// must be done.
+type Policy string
+
+const (
+	// PolicyRequired makes the tool fail before branding if the step
+	// is not configured, and fail if the step fails.
+	PolicyRequired Policy = "required"
+
+	// PolicyOptional makes the tool skip the step with a warning if it
+	// is not configured. This is the default.
+	PolicyOptional Policy = "optional"
+
+	// PolicyDisabled makes the tool skip the step.
+	PolicyDisabled Policy = "disabled"
+)
+
+// UnmarshalJSON accepts only the known policies.
+func (policy *Policy) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch Policy(value) {
+	case PolicyRequired, PolicyOptional, PolicyDisabled:
+		*policy = Policy(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid policy %q, expected %s, %s, or %s", value, PolicyRequired, PolicyOptional, PolicyDisabled)
+	}
+}
+
+// OrDefault returns the policy, or PolicyOptional if it is not set.
+func (policy Policy) OrDefault() Policy {
+	if policy == "" {
+		return PolicyOptional
+	}
+	return policy
+}
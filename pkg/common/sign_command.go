@@ -0,0 +1,118 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CommandLine is a command given either as a single command line string
+// or as an array of the program and its arguments.
+type CommandLine struct {
+	// Line is the command line string. Empty if Argv is set.
+	Line string
+
+	// Argv holds the program and its arguments, passed as is.
+	Argv []string
+}
+
+// UnmarshalJSON accepts either a string or an array of strings.
+func (command *CommandLine) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &command.Argv); err != nil {
+			return err
+		}
+		if len(command.Argv) == 0 {
+			return errors.New("the command array is empty")
+		}
+		return nil
+	}
+	return json.Unmarshal(data, &command.Line)
+}
+
+// Strings returns the command line or the program and its arguments.
+func (command CommandLine) Strings() []string {
+	if command.Argv != nil {
+		return command.Argv
+	}
+	return []string{command.Line}
+}
+
+// GlobCommand is a command applied to the files matching the glob.
+type GlobCommand struct {
+	Glob    string
+	Command CommandLine
+}
+
+// SignCommand is the command signing the Windows binaries.
+//
+// In the JSON file, it can be specified as a command line string, as an array
+// of the program and its arguments, or as an object mapping the file name globs
+// to the commands. The first glob matching the file name applies:
+//
+//	"signCommand": {
+//	  "*.exe": ["signtool", "sign", "/d", "@@PRODUCT_NAME@@", "@@BINARY_PATH@@"],
+//	  "*.dll": "signtool sign \"@@BINARY_PATH@@\""
+//	}
+type SignCommand struct {
+	// Commands holds the commands in the order of the globs. A single command
+	// without globs has the "*" glob.
+	Commands []GlobCommand
+}
+
+// UnmarshalJSON accepts a string, an array, or an object of the glob commands
+// keeping the order of the globs.
+func (signCommand *SignCommand) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		var command CommandLine
+		if err := json.Unmarshal(trimmed, &command); err != nil {
+			return err
+		}
+		signCommand.Commands = []GlobCommand{{Glob: "*", Command: command}}
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	signCommand.Commands = []GlobCommand{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		glob, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("invalid sign command glob %v", token)
+		}
+		var command CommandLine
+		if err := decoder.Decode(&command); err != nil {
+			return fmt.Errorf("invalid sign command for %s: %w", glob, err)
+		}
+		signCommand.Commands = append(signCommand.Commands, GlobCommand{Glob: glob, Command: command})
+	}
+	return nil
+}
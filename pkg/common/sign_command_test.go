@@ -0,0 +1,80 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSignCommandUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    []GlobCommand
+		wantErr bool
+	}{
+		{
+			json: `"signtool sign @@BINARY_PATH@@"`,
+			want: []GlobCommand{{Glob: "*", Command: CommandLine{Line: "signtool sign @@BINARY_PATH@@"}}},
+		},
+		{
+			json: `["signtool", "sign", "@@BINARY_PATH@@"]`,
+			want: []GlobCommand{{Glob: "*", Command: CommandLine{Argv: []string{"signtool", "sign", "@@BINARY_PATH@@"}}}},
+		},
+		{
+			json: `{"*.exe": ["signtool", "@@BINARY_PATH@@"], "chrome*.dll": "sign-chrome @@BINARY_PATH@@", "*": "sign @@BINARY_PATH@@"}`,
+			want: []GlobCommand{
+				{Glob: "*.exe", Command: CommandLine{Argv: []string{"signtool", "@@BINARY_PATH@@"}}},
+				{Glob: "chrome*.dll", Command: CommandLine{Line: "sign-chrome @@BINARY_PATH@@"}},
+				{Glob: "*", Command: CommandLine{Line: "sign @@BINARY_PATH@@"}},
+			},
+		},
+		{
+			// The globs keep the order of the file rather than the sorted order.
+			json: `{"z*": "sign-z @@BINARY_PATH@@", "a*": "sign-a @@BINARY_PATH@@"}`,
+			want: []GlobCommand{
+				{Glob: "z*", Command: CommandLine{Line: "sign-z @@BINARY_PATH@@"}},
+				{Glob: "a*", Command: CommandLine{Line: "sign-a @@BINARY_PATH@@"}},
+			},
+		},
+		{json: `{}`, want: []GlobCommand{}},
+		{json: `[]`, wantErr: true},
+		{json: `{"*.exe": []}`, wantErr: true},
+		{json: `{"*.exe": 1}`, wantErr: true},
+		{json: `1`, wantErr: true},
+	}
+	for _, test := range tests {
+		var signCommand SignCommand
+		err := json.Unmarshal([]byte(test.json), &signCommand)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("unmarshalling %s = %+v, want an error", test.json, signCommand.Commands)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unmarshalling %s failed: %v", test.json, err)
+		} else if !reflect.DeepEqual(signCommand.Commands, test.want) {
+			t.Errorf("unmarshalling %s = %+v, want %+v", test.json, signCommand.Commands, test.want)
+		}
+	}
+}
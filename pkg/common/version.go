@@ -0,0 +1,158 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	macVersionMaxParts = 3
+	winVersionParts    = 4
+	winVersionPartMax  = 0xFFFF
+)
+
+// Version holds the version numbers stamped into the branded binaries.
+//
+// In the JSON file, it can be specified either as a plain string
+// (e.g., "1.2.3") that is used for every version field, or as an object
+// with the separate marketing version, build number, and file version:
+//
+//	"version": {
+//	  "marketing": "1.2.3",
+//	  "build": "1234",
+//	  "file": "1.2.3.1234"
+//	}
+type Version struct {
+	// Marketing is the user-facing version (e.g., "1.2.3"). It becomes
+	// CFBundleShortVersionString on macOS and ProductVersion on Windows.
+	Marketing string
+
+	// Build is the build number (e.g., "1234"). It becomes CFBundleVersion
+	// on macOS. If empty, Marketing is used instead.
+	Build string
+
+	// File is the four-part file version (e.g., "1.2.3.1234"). It becomes
+	// FileVersion on Windows. If empty, Marketing padded with zeros is used.
+	File string
+}
+
+// UnmarshalJSON accepts either a version string or a version object.
+func (version *Version) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &version.Marketing)
+	}
+
+	// The alias type prevents the infinite recursion into this method.
+	type versionObject Version
+	var object versionObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*version = Version(object)
+	return nil
+}
+
+// MarketingVersion returns the user-facing version.
+func (version *Version) MarketingVersion() string {
+	return version.Marketing
+}
+
+// BuildVersion returns the build number, or the marketing version
+// if the build number is not specified.
+func (version *Version) BuildVersion() string {
+	if version.Build != "" {
+		return version.Build
+	}
+	return version.Marketing
+}
+
+// FileVersion returns the four-part file version. If it is not specified,
+// the marketing version is padded with zeros to four parts (e.g., "1.2.3"
+// becomes "1.2.3.0").
+func (version *Version) FileVersion() string {
+	if version.File != "" {
+		return version.File
+	}
+	parts := strings.Split(version.Marketing, ".")
+	for len(parts) < winVersionParts {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".")
+}
+
+// ValidateForMac checks that the marketing version and the build number
+// follow Apple's rules: one to three period-separated non-negative integers.
+func (version *Version) ValidateForMac() error {
+	if err := validateVersionParts(version.MarketingVersion(), 1, macVersionMaxParts, -1); err != nil {
+		return fmt.Errorf("invalid marketing version %q: %w", version.MarketingVersion(), err)
+	}
+	if err := validateVersionParts(version.BuildVersion(), 1, macVersionMaxParts, -1); err != nil {
+		return fmt.Errorf("invalid build number %q: %w", version.BuildVersion(), err)
+	}
+	return nil
+}
+
+// ValidateForWin checks that the file version consists of exactly four
+// 16-bit parts and the product version of at most four 16-bit parts,
+// as required by the Windows VERSIONINFO resource.
+func (version *Version) ValidateForWin() error {
+	if err := validateVersionParts(version.FileVersion(), winVersionParts, winVersionParts, winVersionPartMax); err != nil {
+		return fmt.Errorf("invalid file version %q: %w", version.FileVersion(), err)
+	}
+	if err := validateVersionParts(version.MarketingVersion(), 1, winVersionParts, winVersionPartMax); err != nil {
+		return fmt.Errorf("invalid product version %q: %w", version.MarketingVersion(), err)
+	}
+	return nil
+}
+
+// validateVersionParts checks that the version consists of minParts to maxParts
+// period-separated non-negative integers. If maxValue is not negative,
+// every part must not exceed it.
+func validateVersionParts(version string, minParts, maxParts int, maxValue int) error {
+	if version == "" {
+		return fmt.Errorf("the version is empty")
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) < minParts || len(parts) > maxParts {
+		if minParts == maxParts {
+			return fmt.Errorf("expected %d parts, got %d", maxParts, len(parts))
+		}
+		return fmt.Errorf("expected %d to %d parts, got %d", minParts, maxParts, len(parts))
+	}
+	for _, part := range parts {
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return fmt.Errorf("%q is not a non-negative integer", part)
+		}
+		value, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is out of range", part)
+		}
+		if maxValue >= 0 && value > uint64(maxValue) {
+			return fmt.Errorf("%q exceeds the maximum of %d", part, maxValue)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
+)
+
+// macSigningStep is a piece of code in the app bundle to sign.
+type macSigningStep struct {
+	path         string
+	kind         string
+	entitlements string
+}
+
+// macSigningPlan lists the code of the app bundle in the order of signing.
+//
+// Signing a bundle seals the code inside it, so the nested code must be
+// signed first, or the bundle signature is invalidated ("nested code is
+// modified or invalid"). The steps of a stage are independent, and a stage
+// starts after the previous one completes.
+type macSigningPlan struct {
+	stages [][]macSigningStep
+}
+
+// planMacSigning orders the code found in the app bundle inside-out: every
+// piece of code is signed after all the code inside it, and the app bundle
+// is signed last.
+func planMacSigning(bundlePath string, code []string, entitlements *mac.SigningEntitlements) macSigningPlan {
+	nested := []string{}
+	for _, path := range code {
+		if path != bundlePath {
+			nested = append(nested, path)
+		}
+	}
+
+	plan := macSigningPlan{}
+	for _, stage := range insideOutStages(nested) {
+		steps := []macSigningStep{}
+		for _, path := range stage {
+			steps = append(steps, macSigningStep{path: path, kind: macCodeKind(path), entitlements: entitlements.For(path)})
+		}
+		plan.stages = append(plan.stages, steps)
+	}
+	plan.stages = append(plan.stages, []macSigningStep{{path: bundlePath, kind: "app", entitlements: entitlements.App()}})
+	return plan
+}
+
+// print prints the planned order in the verbose mode.
+func (plan macSigningPlan) print(outDir string) {
+	if !base.Verbose {
+		return
+	}
+	fmt.Println("Signing plan:")
+	for i, stage := range plan.stages {
+		for _, step := range stage {
+			path, err := filepath.Rel(outDir, step.path)
+			if err != nil {
+				path = step.path
+			}
+			fmt.Printf("  %d. %-12s %s (%s)\n", i+1, step.kind, path, filepath.Base(step.entitlements))
+		}
+	}
+}
+
+// insideOutStages splits the paths into the stages to sign one after another,
+// so that every path is signed after all the paths inside it. The paths of
+// the same stage are independent and can be signed concurrently.
+func insideOutStages(paths []string) [][]string {
+	levels := map[string]int{}
+	var level func(path string) int
+	level = func(path string) int {
+		if value, ok := levels[path]; ok {
+			return value
+		}
+		value := 0
+		for _, other := range paths {
+			if other != path && strings.HasPrefix(other, path+string(filepath.Separator)) {
+				if nested := level(other) + 1; nested > value {
+					value = nested
+				}
+			}
+		}
+		levels[path] = value
+		return value
+	}
+
+	stages := [][]string{}
+	for _, path := range paths {
+		value := level(path)
+		for len(stages) <= value {
+			stages = append(stages, []string{})
+		}
+		stages[value] = append(stages[value], path)
+	}
+	return stages
+}
+
+// macCodeKind describes the code at the path as found by findMacCode.
+func macCodeKind(path string) string {
+	if isMacFrameworkVersion(path) {
+		return "framework"
+	}
+	switch filepath.Ext(path) {
+	case ".dylib", ".so":
+		return "library"
+	case ".xpc":
+		return "XPC service"
+	case ".appex", ".plugin":
+		return "plug-in"
+	case ".bundle":
+		return "bundle"
+	case ".app":
+		if filepath.Base(filepath.Dir(path)) == "LoginItems" {
+			return "login item"
+		}
+		return "app"
+	}
+	return "executable"
+}
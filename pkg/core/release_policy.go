@@ -0,0 +1,231 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/win"
+)
+
+// ReleaseStatus describes what has happened to one of the release steps:
+// signing or notarization.
+type ReleaseStatus struct {
+	// Done tells whether the step has been completed.
+	Done bool
+
+	// Reason explains why the step has not been completed.
+	Reason string
+
+	// Skipped are the files left out of the completed step.
+	Skipped []string
+}
+
+// CheckPolicies fails if signing or notarization is required but cannot be
+// done with the given parameters. It is called before branding starts, so
+// that a misconfigured release fails fast.
+func CheckPolicies(outDir string, params common.BrandingParams) error {
+	if params.Signing.OrDefault() == common.PolicyRequired {
+		if applicable, reason := signingSkipReason(outDir, params); applicable && reason != "" {
+			return errors.New("signing is required, but " + reason)
+		}
+	}
+	if params.Notarization.OrDefault() == common.PolicyRequired {
+		applicable, reason := notarizationSkipReason(outDir, params)
+		if applicable && params.Signing.OrDefault() == common.PolicyDisabled {
+			return errors.New("notarization is required, but signing is disabled")
+		}
+		if applicable && reason != "" {
+			return errors.New("notarization is required, but " + reason)
+		}
+	}
+	return nil
+}
+
+// SignApp signs the application binaries according to the signing policy.
+//
+// If signing is optional and cannot be done, prints a warning and skips it.
+// If signing is required and cannot be done, returns an error.
+func SignApp(outDir string, params common.BrandingParams) (ReleaseStatus, error) {
+	policy := params.Signing.OrDefault()
+	if policy == common.PolicyDisabled {
+		return ReleaseStatus{Reason: "disabled"}, nil
+	}
+	applicable, reason := signingSkipReason(outDir, params)
+	if !applicable {
+		return ReleaseStatus{Reason: reason}, nil
+	}
+	if reason != "" {
+		if policy == common.PolicyRequired {
+			return ReleaseStatus{Reason: reason}, errors.New("signing is required, but " + reason)
+		}
+		fmt.Printf("WARNING: skipping signing: %s\n", reason)
+		return ReleaseStatus{Reason: reason}, nil
+	}
+
+	signed, skipped, err := SignAppBinaries(outDir, params)
+	if err != nil {
+		return ReleaseStatus{Reason: err.Error()}, err
+	}
+	if !signed {
+		return ReleaseStatus{Reason: "the sign tool is not configured"}, nil
+	}
+	status := ReleaseStatus{Done: true}
+	for _, path := range skipped {
+		if relPath, err := filepath.Rel(outDir, path); err == nil {
+			path = relPath
+		}
+		status.Skipped = append(status.Skipped, path)
+	}
+	return status, nil
+}
+
+// NotarizeApp notarizes the macOS application bundle according to
+// the notarization policy. The bundle is not notarized if it has not been signed.
+func NotarizeApp(outDir string, params common.BrandingParams, signing ReleaseStatus) (ReleaseStatus, error) {
+	policy := params.Notarization.OrDefault()
+	if policy == common.PolicyDisabled {
+		return ReleaseStatus{Reason: "disabled"}, nil
+	}
+	applicable, reason := notarizationSkipReason(outDir, params)
+	if !applicable {
+		return ReleaseStatus{Reason: reason}, nil
+	}
+	if reason == "" && !signing.Done {
+		reason = "the app is not signed"
+	}
+	if reason != "" {
+		if policy == common.PolicyRequired {
+			return ReleaseStatus{Reason: reason}, errors.New("notarization is required, but " + reason)
+		}
+		fmt.Printf("WARNING: skipping notarization: %s\n", reason)
+		return ReleaseStatus{Reason: reason}, nil
+	}
+
+	notarized, err := mac.Notarize(outDir, params)
+	if err != nil {
+		return ReleaseStatus{Reason: err.Error()}, err
+	}
+	if !notarized {
+		return ReleaseStatus{Reason: "the notarization parameters are not set"}, nil
+	}
+	return ReleaseStatus{Done: true}, nil
+}
+
+// PrintReleaseSummary prints what has and has not been signed or notarized.
+func PrintReleaseSummary(signing, notarization ReleaseStatus) {
+	fmt.Println("Release summary:")
+	fmt.Println("  Signing:      " + signing.String())
+	fmt.Println("  Notarization: " + notarization.String())
+}
+
+func (status ReleaseStatus) String() string {
+	if status.Done && len(status.Skipped) != 0 {
+		return "done, skipped " + strings.Join(status.Skipped, ", ")
+	}
+	if status.Done {
+		return "done"
+	}
+	if status.Reason == "" {
+		return "not done"
+	}
+	return "not done (" + status.Reason + ")"
+}
+
+// signingSkipReason tells whether signing is applicable on this platform and,
+// if so, why it cannot be done with the given parameters. The reason is empty
+// if signing can be done.
+func signingSkipReason(outDir string, params common.BrandingParams) (bool, string) {
+	switch {
+	case signsMacApp(outDir, params):
+		if !mac.IsSigningConfigured(params) {
+			return true, emptyParamReason("mac.codesignIdentity", params.Mac.CodesignIdentity)
+		}
+		if _, err := mac.GetSignToolMac(params); err != nil {
+			return true, err.Error()
+		}
+		if params.Mac.Bundle == nil || params.Mac.Bundle.Name == nil {
+			return true, "mac.bundle.name is not set"
+		}
+	case runtime.GOOS == "windows":
+		if _, err := win.GetSignToolWin(params); err != nil {
+			return true, err.Error()
+		}
+	default:
+		return false, "not supported on " + runtime.GOOS
+	}
+	return true, ""
+}
+
+// notarizationSkipReason tells whether notarization is applicable on this
+// platform and, if so, why it cannot be done with the given parameters.
+// The reason is empty if notarization can be done.
+func notarizationSkipReason(outDir string, params common.BrandingParams) (bool, string) {
+	if runtime.GOOS != "darwin" {
+		return false, "not applicable on " + runtime.GOOS
+	}
+	if _, reason := signingSkipReason(outDir, params); reason != "" {
+		return true, "the app cannot be signed: " + reason
+	}
+	if !mac.UsesAppleIdNotarization(params) {
+		if err := mac.ValidateNotarizationParams(params); err != nil {
+			return true, err.Error()
+		}
+		return true, ""
+	}
+	reasons := []string{}
+	for _, param := range []struct{ name, value string }{
+		{"mac.teamId", params.Mac.TeamId},
+		{"mac.appleId", params.Mac.AppleId},
+		{"mac.password", params.Mac.Password},
+	} {
+		if reason := emptyParamReason(param.name, param.value); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		if err := mac.ValidateNotarizationParams(params); err != nil {
+			return true, err.Error()
+		}
+	}
+	return true, strings.Join(reasons, ", ")
+}
+
+// emptyParamReason describes why the parameter has no value, naming
+// the environment variable if the value refers to one. Returns an empty
+// string if the parameter has a value.
+func emptyParamReason(name, value string) string {
+	if base.GetValue(value) != "" {
+		return ""
+	}
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+		envVar := strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}")
+		return fmt.Sprintf("%s is empty because the %s environment variable is not set", name, envVar)
+	}
+	return name + " is not set"
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+func TestSigningSkipReasonNeedsMacBundleOnLinux(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the macOS app bundle is always signed on macOS, and the binaries on Windows")
+	}
+	name := "Test"
+	params := common.BrandingParams{Mac: common.Mac{
+		CodesignIdentity: "-",
+		Bundle:           &common.Bundle{Name: &name},
+	}}
+	outDir := t.TempDir()
+	if applicable, reason := signingSkipReason(outDir, params); applicable {
+		t.Errorf("signing is applicable without the app bundle, the reason is %q", reason)
+	}
+
+	if err := os.Mkdir(filepath.Join(outDir, name+".app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if applicable, reason := signingSkipReason(outDir, params); !applicable || reason != "" {
+		t.Errorf("signing the app bundle is not applicable or is skipped: %q", reason)
+	}
+}
@@ -21,41 +21,44 @@
 package core
 
 import (
-	"bytes"
-	"encoding/xml"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
 )
 
 // Signs all the required Chromium binaries on macOS and Windows.
-func SignAppBinaries(outDir string, params common.BrandingParams) (bool, error) {
-	if runtime.GOOS == "linux" {
-		return false, nil
+// Returns the files the sign tool has skipped.
+func SignAppBinaries(outDir string, params common.BrandingParams) (bool, []string, error) {
+	if signsMacApp(outDir, params) {
+		signed, err := signMacAppBinaries(outDir, params)
+		return signed, nil, err
 	}
-	if runtime.GOOS == "darwin" {
-		return signMacAppBinaries(outDir, params)
+	if runtime.GOOS == "linux" {
+		return false, nil, nil
 	}
 
 	filesToSign, err := getFilesToSign(outDir, params)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
-	return SignBinaries(params, filesToSign, "application")
+	return SignBinaries(outDir, params, filesToSign, "application")
 }
 
 // Signs the provided `binaries` if there is an available sign tool.
 //
-// If the sign tool is configured incorrectly, skips signing.
+// If the sign tool is configured incorrectly, returns an error.
 // For example, on macOS it can be caused by the invalid `codesign` parameters.
 // On Windows, by the empty sign command or the command without the placeholder to substitute a binary file path.
+// The binaries the sign tool does not sign, e.g. the ones matching no glob
+// of the Windows sign command, are skipped and returned.
 //
 // This function also reports signing status updates based on the provided `binariesGroupName` in the following format:
 // [STATUS] Signing `binariesGroupName` <current status>
@@ -63,78 +66,145 @@ func SignAppBinaries(outDir string, params common.BrandingParams) (bool, error)
 // If signing has succeeded, returns `true`.
 // If signing has been skipped, returns `false`.
 // If signing has failed, returns `false` and error.
-func SignBinaries(params common.BrandingParams, binaries []string, binariesGroupName string) (bool, error) {
-	signTool, err := GetSignTool(params)
+func SignBinaries(outDir string, params common.BrandingParams, binaries []string, binariesGroupName string) (bool, []string, error) {
+	signTool, err := GetSignTool(outDir, params)
 	if err != nil {
-		return false, nil
+		return false, nil, err
 	}
 
-	for _, binaryPath := range binaries {
-		if err := signTool.SignBinary(binaryPath); err != nil {
-			return false, unableToSign(err)
+	runner, err := newSignRunner(params)
+	if err != nil {
+		return false, nil, err
+	}
+	defer runner.printReport()
+
+	skipped := []string{}
+	if selectiveSignTool, ok := signTool.(SelectiveSignTool); ok {
+		toSign := []string{}
+		for _, binary := range binaries {
+			if selectiveSignTool.CanSign(binary) {
+				toSign = append(toSign, binary)
+			} else {
+				fmt.Printf("WARNING: no sign command matches %s, skipping it.\n", binary)
+				skipped = append(skipped, binary)
+			}
 		}
+		binaries = toSign
 	}
 
-	return true, nil
+	if batchSignTool, ok := signTool.(BatchSignTool); ok {
+		err = runner.run(batchSignTool.Batches(binaries), batchSignTool.SignBinaries)
+	} else {
+		err = runner.runEach(binaries, signTool.SignBinary)
+	}
+	if err != nil {
+		return false, nil, unableToSign(err)
+	}
+
+	return true, skipped, nil
 }
 
 func signMacAppBinaries(outDir string, params common.BrandingParams) (bool, error) {
-	signTool, err := GetSignTool(params)
-	if err != nil {
+	if !mac.IsSigningConfigured(params) {
 		return false, nil
 	}
 
+	signTool, err := GetSignTool(outDir, params)
+	if err != nil {
+		return false, err
+	}
+
 	mst, ok := signTool.(MacSignTool)
 	if !ok {
 		return false, errors.New("macOS sign tool does not support entitlements-aware signing")
 	}
 
-	if base.GetValue(params.Mac.CodesignIdentity) == "" {
-		return false, nil
-	}
-
-	helperEntitlements, tempFile, err := prepareForSigning(outDir, params)
+	entitlements, err := prepareForSigning(outDir, params)
 	if err != nil {
 		return false, err
 	}
-	if tempFile != "" {
-		defer os.Remove(tempFile)
-	}
+	defer entitlements.Remove()
 
 	bundleName := *params.Mac.Bundle.Name
 	bundlePath := filepath.Join(outDir, bundleName+".app")
 
-	filesToSign, err := getFilesToSignMac(outDir, bundleName)
+	filesToSign, err := getFilesToSign(outDir, params)
+	if err != nil {
+		return false, err
+	}
+	plan := planMacSigning(bundlePath, filesToSign, entitlements)
+	plan.print(outDir)
+
+	runner, err := newSignRunner(params)
 	if err != nil {
 		return false, err
 	}
+	defer runner.printReport()
 
-	for _, path := range filesToSign {
-		if path == bundlePath {
-			continue
+	for _, stage := range plan.stages {
+		steps := map[string]macSigningStep{}
+		paths := []string{}
+		for _, step := range stage {
+			steps[step.path] = step
+			paths = append(paths, step.path)
 		}
-		if err := mst.SignBinaryWithEntitlements(path, helperEntitlements); err != nil {
+		err := runner.runEach(paths, func(path string) error {
+			return mst.SignBinaryWithEntitlements(path, steps[path].entitlements)
+		})
+		if err != nil {
 			return false, unableToSign(err)
 		}
 	}
 
-	if err := signTool.SignBinary(bundlePath); err != nil {
-		return false, unableToSign(err)
-	}
-
 	return true, nil
 }
 
-// prepareForSigning copies the provisioning profile and builds a filtered
-// helper entitlements file when keychain-access-groups is present.
-// Returns the helper entitlements path and the temp file path (empty if no
-// temp file was created). The caller must remove the temp file when done.
-func prepareForSigning(outDir string, params common.BrandingParams) (helperEntitlements, tempFile string, err error) {
-	entitlementsPath := params.Mac.CodesignEntitlements
+// prepareForSigning selects the entitlements of the code in the app bundle
+// and copies the provisioning profile if the app entitlements contain
+// keychain-access-groups. The caller must remove the temporary entitlements
+// files when done.
+func prepareForSigning(outDir string, params common.BrandingParams) (*mac.SigningEntitlements, error) {
+	if err := lintEntitlements(params); err != nil {
+		return nil, err
+	}
+	entitlements, err := mac.PrepareEntitlements(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := copyProvisioningProfile(outDir, params, entitlements); err != nil {
+		entitlements.Remove()
+		return nil, err
+	}
+	return entitlements, nil
+}
+
+// lintEntitlements prints the problems of the entitlements files, and
+// the comparison with the recommended ones in verbose mode. Returns an error
+// if the entitlements have errors.
+func lintEntitlements(params common.BrandingParams) error {
+	reports, err := mac.LintEntitlements(params)
+	if err != nil {
+		return err
+	}
+	hasErrors := false
+	for _, report := range reports {
+		if base.Verbose {
+			fmt.Print(report.String())
+		} else {
+			fmt.Print(report.Summary())
+		}
+		hasErrors = hasErrors || report.HasErrors()
+	}
+	if hasErrors {
+		return errors.New("the entitlements have errors, run the entitlements command for details")
+	}
+	return nil
+}
 
-	hasKAG, err := hasKeychainAccessGroups(entitlementsPath)
+func copyProvisioningProfile(outDir string, params common.BrandingParams, entitlements *mac.SigningEntitlements) error {
+	hasKAG, err := entitlements.AppHasKeychainAccessGroups()
 	if err != nil {
-		return "", "", err
+		return err
 	}
 
 	bundleName := *params.Mac.Bundle.Name
@@ -143,171 +213,40 @@ func prepareForSigning(outDir string, params common.BrandingParams) (helperEntit
 	// Always remove any pre-existing profile. It would be bound to TeamDev's
 	// Team ID and cause AMFI to reject the re-signed bundle.
 	if err := os.Remove(profileDest); err != nil && !os.IsNotExist(err) {
-		return "", "", fmt.Errorf("removing existing provisioning profile: %w", err)
+		return fmt.Errorf("removing existing provisioning profile: %w", err)
 	}
 
 	if !hasKAG {
-		return entitlementsPath, "", nil
+		return nil
 	}
 
 	profilePath := params.Mac.ProvisioningProfile
 	if profilePath == "" {
-		return "", "", errors.New("entitlements contain keychain-access-groups but no provisioning profile is configured; set mac.provisioningProfile in params.json")
+		return errors.New("entitlements contain keychain-access-groups but no provisioning profile is configured; set mac.provisioningProfile in params.json")
 	}
 	if _, statErr := os.Stat(profilePath); statErr != nil {
 		if os.IsNotExist(statErr) {
-			return "", "", fmt.Errorf("provisioning profile not found: %s", profilePath)
-		}
-		return "", "", fmt.Errorf("checking provisioning profile %s: %w", profilePath, statErr)
-	}
-
-	if err := base.CopyFile(profilePath, profileDest); err != nil {
-		return "", "", fmt.Errorf("copying provisioning profile: %w", err)
-	}
-
-	tempPath, err := writeHelperEntitlements(entitlementsPath)
-	if err != nil {
-		return "", "", err
-	}
-
-	return tempPath, tempPath, nil
-}
-
-// hasKeychainAccessGroups reports whether the plist at entitlementsPath
-// contains a keychain-access-groups key.
-func hasKeychainAccessGroups(entitlementsPath string) (bool, error) {
-	data, err := os.ReadFile(entitlementsPath)
-	if err != nil {
-		return false, fmt.Errorf("reading entitlements %s: %w", entitlementsPath, err)
-	}
-	decoder := xml.NewDecoder(bytes.NewReader(data))
-	inKey := false
-	for {
-		tok, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return false, fmt.Errorf("parsing entitlements: %w", err)
-		}
-		switch t := tok.(type) {
-		case xml.StartElement:
-			if t.Name.Local == "key" {
-				inKey = true
-			}
-		case xml.EndElement:
-			inKey = false
-		case xml.CharData:
-			if inKey && strings.TrimSpace(string(t)) == "keychain-access-groups" {
-				return true, nil
-			}
+			return fmt.Errorf("provisioning profile not found: %s", profilePath)
 		}
-	}
-	return false, nil
-}
-
-// writeHelperEntitlements writes a copy of the plist at entitlementsPath
-// with the keychain-access-groups key/value pair removed to a temp file.
-// Returns the temp file path; the caller is responsible for removing it.
-func writeHelperEntitlements(entitlementsPath string) (string, error) {
-	data, err := os.ReadFile(entitlementsPath)
-	if err != nil {
-		return "", fmt.Errorf("reading entitlements: %w", err)
+		return fmt.Errorf("checking provisioning profile %s: %w", profilePath, statErr)
 	}
 
-	tmpFile, err := os.CreateTemp("", "helper-entitlements-*.plist")
+	profile, err := mac.ReadProvisioningProfile(profilePath)
 	if err != nil {
-		return "", fmt.Errorf("creating temp entitlements: %w", err)
+		return err
 	}
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("writing temp entitlements: %w", err)
+	base.Logf("Provisioning profile: %s (%s) of the team %s, expires on %s", profile.Name, profile.UUID,
+		strings.Join(profile.TeamIdentifiers, ", "), profile.ExpirationDate.Format(time.DateOnly))
+	if err := profile.Check(params, entitlements.App()); err != nil {
+		return err
 	}
-	tmpFile.Close()
 
-	if err := base.ExecCommand("plutil", []string{"-remove", "keychain-access-groups", tmpFile.Name()}); err != nil {
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("removing keychain-access-groups from entitlements: %w", err)
+	if err := base.CopyFile(profilePath, profileDest); err != nil {
+		return fmt.Errorf("copying provisioning profile: %w", err)
 	}
-
-	return tmpFile.Name(), nil
+	return nil
 }
 
 func unableToSign(internalError error) error {
 	return errors.New("unable to sign binaries: " + internalError.Error())
 }
-
-func getFilesToSign(outBinDir string, params common.BrandingParams) ([]string, error) {
-	switch runtime.GOOS {
-	case "windows":
-		return getFilesToSignWin(outBinDir)
-	case "darwin":
-		return getFilesToSignMac(outBinDir, *params.Mac.Bundle.Name)
-	default:
-		return []string{}, errors.New("cannot sign binaries on the platform: " + runtime.GOOS)
-	}
-}
-
-func getFilesToSignWin(outBinDir string) ([]string, error) {
-	return getFilesFromDirectoryRoot(outBinDir, []string{"exe", "dll"})
-}
-
-func getFilesToSignMac(outBinDir string, bundleName string) ([]string, error) {
-	bundlePath := filepath.Join(outBinDir, bundleName+".app")
-	currentVersionDir := filepath.Join(bundlePath, "Contents",
-		"Frameworks", "Chromium Framework.framework", "Versions", "Current")
-	libraries := filepath.Join(currentVersionDir, "Libraries")
-	helpers := filepath.Join(currentVersionDir, "Helpers")
-	fileExtensionsToSeek := map[string][]string{
-		libraries: {"dylib"},
-		helpers:   {"", "app"},
-	}
-	filesToSign := []string{}
-	for directory, binaryFilesExtensions := range fileExtensionsToSeek {
-		if files, err := getFilesFromDirectoryRoot(directory, binaryFilesExtensions); err == nil {
-			filesToSign = append(filesToSign, files...)
-		} else {
-			return filesToSign, err
-		}
-	}
-
-	filesToSign = append(filesToSign, currentVersionDir)
-	filesToSign = append(filesToSign, bundlePath)
-
-	return filesToSign, nil
-}
-
-func getFilesFromDirectoryRoot(directory string, extensions []string) ([]string, error) {
-	files := []string{}
-	entries, err := os.ReadDir(directory)
-	if err != nil {
-		return files, err
-	}
-	for _, entry := range entries {
-		if !entry.IsDir() || getFileExtension(entry.Name()) != "" {
-			if mathesOneOfExtensions(entry.Name(), extensions) {
-				files = append(files, filepath.Join(directory, entry.Name()))
-			}
-		}
-	}
-	return files, nil
-}
-
-func mathesOneOfExtensions(filename string, extensions []string) bool {
-	for _, extension := range extensions {
-		if getFileExtension(filename) == extension {
-			return true
-		}
-	}
-	return false
-}
-
-func getFileExtension(filename string) string {
-	splitParts := strings.Split(filename, ".")
-	if len(splitParts) == 1 {
-		return ""
-	} else {
-		return splitParts[len(splitParts)-1]
-	}
-}
@@ -0,0 +1,218 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	defaultSignConcurrency = 1
+	defaultSignRetries     = 2
+	defaultSignRetryDelay  = 5 * time.Second
+)
+
+// defaultRetryPatterns match the errors of the timestamp servers and the network
+// errors reported by signtool and codesign.
+var defaultRetryPatterns = []string{
+	`(?i)time ?stamp`,
+	`(?i)timed out|timeout`,
+	`(?i)connection (was )?(reset|refused|closed)`,
+	`(?i)network|could not be reached|service is not available`,
+}
+
+// signOutcome is the result of signing a file.
+type signOutcome struct {
+	path     string
+	attempts int
+	duration time.Duration
+	err      error
+}
+
+// signRunner signs the files on a bounded pool of workers and retries
+// the failed sign commands with an exponential backoff.
+type signRunner struct {
+	concurrency   int
+	retries       int
+	retryDelay    time.Duration
+	retryPatterns []*regexp.Regexp
+
+	mutex    sync.Mutex
+	outcomes []signOutcome
+}
+
+// newSignRunner creates a signRunner according to params.SigningOptions.
+func newSignRunner(params common.BrandingParams) (*signRunner, error) {
+	runner := &signRunner{
+		concurrency: defaultSignConcurrency,
+		retries:     defaultSignRetries,
+		retryDelay:  defaultSignRetryDelay,
+	}
+	patterns := defaultRetryPatterns
+	if options := params.SigningOptions; options != nil {
+		if options.Concurrency != nil {
+			if *options.Concurrency < 1 {
+				return nil, fmt.Errorf("invalid signing concurrency %d", *options.Concurrency)
+			}
+			runner.concurrency = *options.Concurrency
+		}
+		if options.Retries != nil {
+			if *options.Retries < 0 {
+				return nil, fmt.Errorf("invalid number of signing retries %d", *options.Retries)
+			}
+			runner.retries = *options.Retries
+		}
+		if options.RetryDelay != nil {
+			if *options.RetryDelay < 0 {
+				return nil, fmt.Errorf("invalid signing retry delay %v", *options.RetryDelay)
+			}
+			runner.retryDelay = time.Duration(*options.RetryDelay * float64(time.Second))
+		}
+		if options.RetryPatterns != nil {
+			patterns = options.RetryPatterns
+		}
+	}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing retry pattern %s: %w", pattern, err)
+		}
+		runner.retryPatterns = append(runner.retryPatterns, compiled)
+	}
+	return runner, nil
+}
+
+// run signs the groups of files, at most runner.concurrency groups at a time.
+// Every group is signed by a single call of sign. Returns the first error
+// after all the started groups are done; no new groups start after an error.
+func (runner *signRunner) run(groups [][]string, sign func([]string) error) error {
+	jobs := make(chan []string)
+	var firstErr error
+	var errMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < runner.concurrency && i < len(groups); i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for group := range jobs {
+				if err := runner.signWithRetries(group, sign); err != nil {
+					errMutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, group := range groups {
+		errMutex.Lock()
+		failed := firstErr != nil
+		errMutex.Unlock()
+		if failed {
+			break
+		}
+		jobs <- group
+	}
+	close(jobs)
+	waitGroup.Wait()
+	return firstErr
+}
+
+// runEach signs every file separately.
+func (runner *signRunner) runEach(paths []string, sign func(string) error) error {
+	groups := [][]string{}
+	for _, path := range paths {
+		groups = append(groups, []string{path})
+	}
+	return runner.run(groups, func(group []string) error {
+		return sign(group[0])
+	})
+}
+
+// signWithRetries signs the group, retrying while the error matches the retry
+// patterns, and records the outcome for every file of the group.
+func (runner *signRunner) signWithRetries(group []string, sign func([]string) error) error {
+	start := time.Now()
+	delay := runner.retryDelay
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		if err = sign(group); err == nil || attempts > runner.retries || !runner.isRetryable(err) {
+			break
+		}
+		fmt.Printf("WARNING: signing %s failed, retrying in %v: %v\n", strings.Join(group, ", "), delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	for _, path := range group {
+		runner.outcomes = append(runner.outcomes, signOutcome{path: path, attempts: attempts, duration: time.Since(start), err: err})
+	}
+	return err
+}
+
+// isRetryable reports whether the error matches any of the retry patterns.
+// For a failed command, only its output is matched, since the command line
+// may contain a timestamp server URL that would match any failure.
+func (runner *signRunner) isRetryable(err error) bool {
+	text := err.Error()
+	var commandError *base.CommandError
+	if errors.As(err, &commandError) {
+		text = commandError.Output
+	}
+	for _, pattern := range runner.retryPatterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// printReport prints the outcome of signing every file.
+func (runner *signRunner) printReport() {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	if len(runner.outcomes) == 0 {
+		return
+	}
+	fmt.Println("Signing report:")
+	for _, outcome := range runner.outcomes {
+		status, reason := "signed", ""
+		if outcome.err != nil {
+			status, reason = "FAILED", ": "+strings.SplitN(outcome.err.Error(), "\n", 2)[0]
+		}
+		fmt.Printf("  %-6s %d attempt(s) %8s  %s%s\n", status, outcome.attempts, outcome.duration.Round(time.Millisecond), outcome.path, reason)
+	}
+}
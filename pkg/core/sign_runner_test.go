@@ -0,0 +1,55 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+func TestIsRetryableMatchesCommandOutputOnly(t *testing.T) {
+	runner, err := newSignRunner(common.BrandingParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := &base.CommandError{
+		Command: "signtool.exe",
+		Output:  "SignTool Error: The specified PFX password is not correct.",
+		Err:     errors.New("exit status 1"),
+	}
+	if runner.isRetryable(fmt.Errorf("signing failed: %w", failed)) {
+		t.Error("a wrong password is retried")
+	}
+
+	failed.Output = "SignTool Error: The specified timestamp server either could not be reached or returned an invalid response."
+	if !runner.isRetryable(failed) {
+		t.Error("a timestamp server failure is not retried")
+	}
+
+	if !runner.isRetryable(errors.New("cannot get the timestamp: connection reset")) {
+		t.Error("a timestamp failure of the tool itself is not retried")
+	}
+}
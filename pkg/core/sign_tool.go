@@ -22,6 +22,8 @@ package core
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"runtime"
 
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
@@ -35,6 +37,27 @@ type SignTool interface {
 	SignBinary(binaryPath string) error
 }
 
+// BatchSignTool extends SignTool with signing many binaries at once, used
+// if a single invocation of the signing command can sign several files.
+type BatchSignTool interface {
+	SignTool
+
+	// Batches splits the binaries into the groups passed to SignBinaries.
+	Batches(binaryPaths []string) [][]string
+
+	// SignBinaries signs the binaries of a group.
+	SignBinaries(binaryPaths []string) error
+}
+
+// SelectiveSignTool extends SignTool with the files it signs, used if
+// the configuration of the tool leaves some files unsigned.
+type SelectiveSignTool interface {
+	SignTool
+
+	// CanSign tells whether the tool signs the binary.
+	CanSign(binaryPath string) bool
+}
+
 // MacSignTool extends SignTool with entitlements-aware signing, used to sign
 // helper bundles and dylibs with a filtered entitlements file that omits
 // keychain-access-groups.
@@ -43,14 +66,35 @@ type MacSignTool interface {
 	SignBinaryWithEntitlements(binaryPath, entitlements string) error
 }
 
-// Tries to obtain the sign tool for the current platform.
-func GetSignTool(params common.BrandingParams) (SignTool, error) {
+// Tries to obtain the sign tool for the current platform, or for the macOS
+// app bundle in `outDir`.
+func GetSignTool(outDir string, params common.BrandingParams) (SignTool, error) {
+	if signsMacApp(outDir, params) {
+		return mac.GetSignToolMac(params)
+	}
 	switch runtime.GOOS {
 	case "windows":
 		return win.GetSignToolWin(params)
-	case "darwin":
-		return mac.GetSignToolMac(params)
 	default:
 		return nil, errors.New("signing app binaries on " + runtime.GOOS + " is not supported")
 	}
 }
+
+// signsMacApp tells whether the macOS app bundle is signed: always on macOS,
+// and on other platforms if the bundle is in `outDir`. On Windows,
+// `win.signCommand` takes precedence.
+func signsMacApp(outDir string, params common.BrandingParams) bool {
+	switch runtime.GOOS {
+	case "darwin":
+		return true
+	case "windows":
+		if params.Win.SignCommand != nil {
+			return false
+		}
+	}
+	if params.Mac.Bundle == nil || params.Mac.Bundle.Name == nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(outDir, *params.Mac.Bundle.Name+".app"))
+	return err == nil && info.IsDir()
+}
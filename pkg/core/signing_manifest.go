@@ -0,0 +1,193 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/mac"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/win"
+)
+
+// macCodeBundleExtensions are the extensions of the bundle directories
+// that carry their own code signature.
+var macCodeBundleExtensions = []string{".app", ".framework", ".xpc", ".appex", ".bundle", ".plugin"}
+
+// getFilesToSign finds the binaries to sign in the output directory and
+// selects them according to the signing manifest.
+func getFilesToSign(outDir string, params common.BrandingParams) ([]string, error) {
+	var files []string
+	var err error
+	switch {
+	case signsMacApp(outDir, params):
+		files, err = findMacCode(filepath.Join(outDir, *params.Mac.Bundle.Name+".app"))
+	case runtime.GOOS == "windows":
+		files, err = findPEFiles(outDir)
+	default:
+		return []string{}, errors.New("cannot sign binaries on the platform: " + runtime.GOOS)
+	}
+	if err != nil {
+		return []string{}, err
+	}
+	return selectFilesToSign(outDir, files, params.SigningManifest)
+}
+
+// findPEFiles returns the PE files in the directory and its subdirectories.
+func findPEFiles(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() && win.IsPEFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// findMacCode returns the code to sign in the app bundle: the Mach-O files,
+// the nested bundles, the framework versions, and the app bundle itself.
+//
+// The main executables of the bundles are not returned, since they are
+// signed as part of their bundles.
+func findMacCode(bundlePath string) ([]string, error) {
+	code := []string{}
+	err := filepath.WalkDir(bundlePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			return nil
+		case entry.IsDir():
+			if isMacFrameworkVersion(path) || (isMacCodeBundle(path) && filepath.Ext(path) != ".framework") {
+				code = append(code, path)
+			}
+		case entry.Type().IsRegular():
+			if !isMacMainExecutable(path) && mac.IsMachOFile(path) {
+				code = append(code, path)
+			}
+		}
+		return nil
+	})
+	return code, err
+}
+
+func isMacCodeBundle(path string) bool {
+	return base.Contains(macCodeBundleExtensions, filepath.Ext(path))
+}
+
+// isMacFrameworkVersion reports whether the path is a version directory
+// of a framework, such as `Foo.framework/Versions/A`.
+func isMacFrameworkVersion(path string) bool {
+	versions := filepath.Dir(path)
+	return filepath.Base(versions) == "Versions" && filepath.Ext(filepath.Dir(versions)) == ".framework"
+}
+
+// isMacMainExecutable reports whether the file is the main executable of
+// a bundle (`Foo.app/Contents/MacOS/Foo`) or a framework (`Foo.framework/Versions/A/Foo`).
+func isMacMainExecutable(path string) bool {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "MacOS" && filepath.Base(filepath.Dir(dir)) == "Contents" {
+		return isMacCodeBundle(filepath.Dir(filepath.Dir(dir)))
+	}
+	if isMacFrameworkVersion(dir) {
+		framework := filepath.Base(filepath.Dir(filepath.Dir(dir)))
+		return filepath.Base(path) == strings.TrimSuffix(framework, ".framework")
+	}
+	return false
+}
+
+// selectFilesToSign filters the files by the include and exclude globs
+// matched against their paths relative to the output directory, and skips
+// the files with a valid signature of a publisher listed in SkipSignedBy.
+func selectFilesToSign(outDir string, files []string, manifest *common.SigningManifest) ([]string, error) {
+	if manifest == nil {
+		manifest = &common.SigningManifest{}
+	}
+	selected := []string{}
+	for _, file := range files {
+		relPath, err := filepath.Rel(outDir, file)
+		if err != nil {
+			return nil, err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if len(manifest.Include) != 0 && !matchesAnyGlob(manifest.Include, relPath) {
+			continue
+		}
+		if matchesAnyGlob(manifest.Exclude, relPath) {
+			continue
+		}
+		if len(manifest.SkipSignedBy) != 0 {
+			if signer := keptSigner(file, manifest.SkipSignedBy); signer != "" {
+				fmt.Println("Skipping " + relPath + ": signed by " + signer)
+				continue
+			}
+		}
+		selected = append(selected, file)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+func matchesAnyGlob(globs []string, relPath string) bool {
+	for _, glob := range globs {
+		if base.MatchGlob(glob, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// keptSigner returns the publisher of the valid signature of the file if it
+// is one of the given publishers, or an empty string otherwise.
+func keptSigner(path string, publishers []string) string {
+	var names []string
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		names, err = win.ValidSignerNames(path)
+	case "darwin":
+		names, err = mac.ValidSignerNames(path)
+	}
+	if err != nil {
+		base.Logf("The signature of %s is not kept: %s", path, err)
+		return ""
+	}
+	for _, name := range names {
+		for _, publisher := range publishers {
+			if strings.EqualFold(name, publisher) {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -78,5 +78,12 @@ func (branding *LinuxBranding) Apply(params *common.BrandingParams, binariesDir
 		}
 	}
 
+	if params.Icon != nil {
+		fmt.Println("Generating the product logos from " + params.Icon.Path)
+		if err := writeProductLogos(params.Icon, binariesDir); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package linux
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// productLogoSizes lists the sizes of the product_logo_<size>.png images
+// that Chromium distributions ship on Linux for the desktop entries
+// and the window icons.
+var productLogoSizes = []int{16, 24, 32, 48, 64, 128, 256}
+
+// writeProductLogos generates the product_logo_<size>.png images of all
+// productLogoSizes from the master icon and writes them into binariesDir,
+// replacing the existing ones.
+func writeProductLogos(icon *common.Icon, binariesDir base.Directory) error {
+	for _, size := range productLogoSizes {
+		data, err := icon.PNG(size)
+		if err != nil {
+			return err
+		}
+		logoPath := binariesDir.AbsPath().Join(base.RelPathFromEntries(fmt.Sprintf("product_logo_%d.png", size)))
+		base.Log("Writing " + logoPath.String())
+		if err := os.WriteFile(logoPath.String(), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,6 +21,9 @@
 package mac
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
 )
@@ -28,7 +31,11 @@ import (
 // MacBranding implements branding logic specific to macOS.
 // It overrides Info.plist properties, configures icons, and renames
 // the top-level .app directory to match the branding parameters.
-type MacBranding struct{}
+type MacBranding struct {
+	// icnsPath is the path to the .icns file to install into the bundles,
+	// resolved by Apply from the branding parameters.
+	icnsPath *base.AbsPath
+}
 
 // GetPlatformBranding returns a new MacBranding instance for macOS platforms.
 func GetPlatformBranding() (*MacBranding, error) {
@@ -37,8 +44,8 @@ func GetPlatformBranding() (*MacBranding, error) {
 
 // ApplyToBundle applies macOS-specific branding changes to the provided ChromiumAppBundle.
 // This includes setting Info.plist keys (bundle identifier, bundle name, etc.)
-// and optionally replacing the .icns file if params.Mac.IcnsPath
-// is provided.
+// and optionally replacing the .icns file with the icon resolved by Apply
+// from params.Mac.IcnsPath, params.Mac.IconsetPath, or params.Icon.
 //
 // Parameters:
 //   - params: The BrandingParams containing user-specified overrides.
@@ -63,12 +70,8 @@ func (branding *MacBranding) ApplyToBundle(params *common.BrandingParams, appBun
 		return err
 	}
 
-	if iconExpectedFor(appBundle) && params.Mac.IcnsPath != nil {
-		iconPath, err := base.AbsPathFromPathString(*params.Mac.IcnsPath)
-		if err != nil {
-			return err
-		}
-		if err := configureBundleIcon(appBundle, iconPath); err != nil {
+	if iconExpectedFor(appBundle) && branding.icnsPath != nil {
+		if err := configureBundleIcon(appBundle, *branding.icnsPath); err != nil {
 			return err
 		}
 		plistFile, err := appBundle.PlistFilePath().AsFile()
@@ -94,6 +97,12 @@ func (branding *MacBranding) CheckBinariesExist(binariesDir base.Directory) erro
 }
 
 func (branding *MacBranding) Apply(params *common.BrandingParams, binariesDir base.Directory) error {
+	if params.Version != nil {
+		if err := params.Version.ValidateForMac(); err != nil {
+			return err
+		}
+	}
+
 	rootBundle, err := GetChromiumAppBundle(binariesDir, originalChromiumAppBundleName)
 	if err != nil {
 		return err
@@ -105,6 +114,12 @@ func (branding *MacBranding) Apply(params *common.BrandingParams, binariesDir ba
 		}
 	}
 
+	cleanupIcon, err := branding.resolveIcon(params)
+	if err != nil {
+		return err
+	}
+	defer cleanupIcon()
+
 	allBundles := append([]ChromiumAppBundle{rootBundle.ChromiumAppBundle()}, rootBundle.Helpers()...)
 
 	for _, bundle := range allBundles {
@@ -116,6 +131,50 @@ func (branding *MacBranding) Apply(params *common.BrandingParams, binariesDir ba
 	return nil
 }
 
+// resolveIcon determines the .icns file to install into the bundles. The explicit
+// params.Mac.IcnsPath takes precedence over params.Mac.IconsetPath and then over
+// params.Icon, from which the .icns file is built into a temporary file.
+//
+// Returns a function that removes the temporary file, if any.
+func (branding *MacBranding) resolveIcon(params *common.BrandingParams) (func(), error) {
+	branding.icnsPath = nil
+	noCleanup := func() {}
+
+	if params.Mac.IcnsPath != nil {
+		if params.Mac.IconsetPath != nil {
+			fmt.Println("Both mac.icnsPath and mac.iconsetPath are set, using " + *params.Mac.IcnsPath)
+		}
+		iconPath, err := base.AbsPathFromPathString(*params.Mac.IcnsPath)
+		if err != nil {
+			return noCleanup, err
+		}
+		branding.icnsPath = &iconPath
+		return noCleanup, nil
+	}
+
+	if params.Mac.IconsetPath != nil {
+		fmt.Println("Building the .icns file from " + *params.Mac.IconsetPath)
+		iconPath, err := writeICNSFromIconset(*params.Mac.IconsetPath)
+		if err != nil {
+			return noCleanup, err
+		}
+		branding.icnsPath = &iconPath
+		return func() { os.Remove(iconPath.String()) }, nil
+	}
+
+	if params.Icon != nil {
+		fmt.Println("Generating the .icns file from " + params.Icon.Path)
+		iconPath, err := writeICNSFromIcon(params.Icon)
+		if err != nil {
+			return noCleanup, err
+		}
+		branding.icnsPath = &iconPath
+		return func() { os.Remove(iconPath.String()) }, nil
+	}
+
+	return noCleanup, nil
+}
+
 func (branding *MacBranding) ExecutableNameFile(params *common.BrandingParams, binariesDir base.Directory) (common.ExecutableNameFile, error) {
 	executableName := branding.ExecutableName(params)
 	mainBundle, err := GetChromiumAppBundle(binariesDir, executableName)
@@ -157,6 +216,10 @@ var bundleVersionProperties = []string{
 	"CFBundleShortVersionString",
 }
 
+var bundleBuildVersionProperties = []string{
+	"CFBundleVersion",
+}
+
 func iconExpectedFor(appBundle ChromiumAppBundle) bool {
 	return appBundle.GetType() == CrBundleMain || appBundle.GetType() == CrBundleHelperAlerts
 }
@@ -246,9 +309,12 @@ func overrideBundleId(bundle ChromiumAppBundle, id *string) error {
 	return overrideBundleProperties(bundle, bundleIdProperties, getBrandedCrBundleId(bundle.GetType(), *id))
 }
 
-func overrideBundleVersion(bundle ChromiumAppBundle, version *string) error {
+func overrideBundleVersion(bundle ChromiumAppBundle, version *common.Version) error {
 	if version == nil {
 		return nil
 	}
-	return overrideBundleProperties(bundle, bundleVersionProperties, *version)
+	if err := overrideBundleProperties(bundle, bundleVersionProperties, version.MarketingVersion()); err != nil {
+		return err
+	}
+	return overrideBundleProperties(bundle, bundleBuildVersionProperties, version.BuildVersion())
 }
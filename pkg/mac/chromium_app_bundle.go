@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
 )
 
 const originalChromiumAppBundleName = "Chromium"
@@ -58,8 +59,9 @@ const (
 // AppInfo holds metadata about a Chromium macOS bundle, including
 // version, executable name, and bundle identifier.
 type AppInfo struct {
-	// Version indicates the application version (e.g., "1.0.0").
-	Version *string
+	// Version indicates the application version (e.g., "1.0.0")
+	// and the build number.
+	Version *common.Version
 
 	// ExecutableName is the name of the main executable within the bundle.
 	ExecutableName *string
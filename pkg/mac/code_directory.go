@@ -0,0 +1,340 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	blobMagicRequirements      = 0xFADE0C01
+	blobMagicCodeDirectory     = 0xFADE0C02
+	blobMagicEmbeddedSignature = 0xFADE0CC0
+	blobMagicEntitlements      = 0xFADE7171
+	blobMagicDEREntitlements   = 0xFADE7172
+	blobMagicBlobWrapper       = 0xFADE0B01
+
+	slotCodeDirectory   = 0
+	slotInfoPlist       = 1
+	slotRequirements    = 2
+	slotResources       = 3
+	slotEntitlements    = 5
+	slotDEREntitlements = 7
+	slotSignature       = 0x10000
+
+	codeDirectoryVersion    = 0x20400
+	codeDirectoryHeaderSize = 88
+	codeSignFlagAdhoc       = 0x2
+	codeSignFlagRuntime     = 0x10000
+	execSegMainBinary       = 0x1
+	hashTypeSHA256          = 2
+	codePageShift           = 12
+	codePageSize            = 1 << codePageShift
+	cdHashSize              = 20
+)
+
+// codeSignatureOptions describes the signature of a Mach-O file.
+type codeSignatureOptions struct {
+	identifier string
+	teamID     string
+	adhoc      bool
+	runtime    bool
+
+	// entitlements is the content of the entitlements plist, or nil.
+	entitlements []byte
+
+	// requirements is the requirement set with the designated requirement,
+	// or nil for an empty one.
+	requirements []byte
+
+	// infoPlistHash and resourcesHash bind the Info.plist and
+	// the CodeResources files of the bundle to its main executable.
+	infoPlistHash []byte
+	resourcesHash []byte
+}
+
+// cmsSignFunc signs the CodeDirectory blob and returns the CMS signature.
+type cmsSignFunc func(codeDirectory []byte) ([]byte, error)
+
+// signSlice writes the code signature into the slice and returns the CDHash.
+// cmsSize is the room reserved for the CMS signature, 0 for ad-hoc signing.
+func signSlice(slice *machOSlice, options codeSignatureOptions, sign cmsSignFunc, cmsSize int) ([]byte, error) {
+	codeLimit, err := slice.codeLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := map[uint32][]byte{slotRequirements: options.requirements}
+	if options.requirements == nil {
+		blobs[slotRequirements] = makeBlob(blobMagicRequirements, make([]byte, 4))
+	}
+	if options.entitlements != nil {
+		der, err := encodeEntitlementsDER(options.entitlements)
+		if err != nil {
+			return nil, err
+		}
+		blobs[slotEntitlements] = makeBlob(blobMagicEntitlements, options.entitlements)
+		blobs[slotDEREntitlements] = makeBlob(blobMagicDEREntitlements, der)
+	}
+	specialSlots := map[uint32][]byte{}
+	for slot, blob := range blobs {
+		hash := sha256.Sum256(blob)
+		specialSlots[slot] = hash[:]
+	}
+	if options.infoPlistHash != nil {
+		specialSlots[slotInfoPlist] = options.infoPlistHash
+	}
+	if options.resourcesHash != nil {
+		specialSlots[slotResources] = options.resourcesHash
+	}
+
+	codeDirectorySize := codeDirectoryLength(options, specialSlots, codeLimit)
+	superBlobSize := 12 + (len(blobs)+2)*8 + codeDirectorySize + 8 + cmsSize
+	for _, blob := range blobs {
+		superBlobSize += len(blob)
+	}
+	slice.reserveSignature(codeLimit, int(alignUp64(uint64(superBlobSize), 16)))
+
+	codeDirectory := buildCodeDirectory(slice, options, specialSlots, codeLimit)
+	blobs[slotCodeDirectory] = codeDirectory
+	cms := []byte{}
+	if sign != nil {
+		if cms, err = sign(codeDirectory); err != nil {
+			return nil, err
+		}
+		if len(cms) > cmsSize {
+			return nil, fmt.Errorf("the CMS signature takes %d bytes, more than the reserved %d", len(cms), cmsSize)
+		}
+	}
+	blobs[slotSignature] = makeBlob(blobMagicBlobWrapper, cms)
+
+	superBlob := makeSuperBlob(blobs)
+	copy(slice.data[codeLimit:], superBlob)
+	hash := sha256.Sum256(codeDirectory)
+	return hash[:cdHashSize], nil
+}
+
+func makeBlob(magic uint32, payload []byte) []byte {
+	blob := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(blob, magic)
+	binary.BigEndian.PutUint32(blob[4:], uint32(len(blob)))
+	copy(blob[8:], payload)
+	return blob
+}
+
+// makeSuperBlob builds the embedded signature with the blobs in the order of their slots.
+func makeSuperBlob(blobs map[uint32][]byte) []byte {
+	slots := []uint32{}
+	for _, slot := range []uint32{slotCodeDirectory, slotRequirements, slotEntitlements, slotDEREntitlements, slotSignature} {
+		if _, ok := blobs[slot]; ok {
+			slots = append(slots, slot)
+		}
+	}
+	header := make([]byte, 12+len(slots)*8)
+	binary.BigEndian.PutUint32(header, blobMagicEmbeddedSignature)
+	binary.BigEndian.PutUint32(header[8:], uint32(len(slots)))
+	body := []byte{}
+	for i, slot := range slots {
+		binary.BigEndian.PutUint32(header[12+i*8:], slot)
+		binary.BigEndian.PutUint32(header[16+i*8:], uint32(len(header)+len(body)))
+		body = append(body, blobs[slot]...)
+	}
+	out := append(header, body...)
+	binary.BigEndian.PutUint32(out[4:], uint32(len(out)))
+	return out
+}
+
+func specialSlotCount(specialSlots map[uint32][]byte) int {
+	count := 0
+	for slot := range specialSlots {
+		if int(slot) > count {
+			count = int(slot)
+		}
+	}
+	return count
+}
+
+func codeSlotCount(codeLimit int) int {
+	return (codeLimit + codePageSize - 1) / codePageSize
+}
+
+func codeDirectoryLength(options codeSignatureOptions, specialSlots map[uint32][]byte, codeLimit int) int {
+	length := codeDirectoryHeaderSize + len(options.identifier) + 1
+	if options.teamID != "" {
+		length += len(options.teamID) + 1
+	}
+	return length + (specialSlotCount(specialSlots)+codeSlotCount(codeLimit))*sha256.Size
+}
+
+func buildCodeDirectory(slice *machOSlice, options codeSignatureOptions, specialSlots map[uint32][]byte, codeLimit int) []byte {
+	specialCount := specialSlotCount(specialSlots)
+	codeCount := codeSlotCount(codeLimit)
+	cd := make([]byte, codeDirectoryLength(options, specialSlots, codeLimit))
+
+	flags := uint32(0)
+	if options.adhoc {
+		flags |= codeSignFlagAdhoc
+	}
+	if options.runtime {
+		flags |= codeSignFlagRuntime
+	}
+	identOffset := codeDirectoryHeaderSize
+	teamOffset := 0
+	hashOffset := identOffset + len(options.identifier) + 1
+	if options.teamID != "" {
+		teamOffset = hashOffset
+		hashOffset += len(options.teamID) + 1
+	}
+	hashOffset += specialCount * sha256.Size
+
+	execSegFlags := uint64(0)
+	if slice.fileType == machOFileTypeExecute {
+		execSegFlags = execSegMainBinary
+	}
+
+	be := binary.BigEndian
+	be.PutUint32(cd[0:], blobMagicCodeDirectory)
+	be.PutUint32(cd[4:], uint32(len(cd)))
+	be.PutUint32(cd[8:], codeDirectoryVersion)
+	be.PutUint32(cd[12:], flags)
+	be.PutUint32(cd[16:], uint32(hashOffset))
+	be.PutUint32(cd[20:], uint32(identOffset))
+	be.PutUint32(cd[24:], uint32(specialCount))
+	be.PutUint32(cd[28:], uint32(codeCount))
+	be.PutUint32(cd[32:], uint32(codeLimit))
+	cd[36] = sha256.Size
+	cd[37] = hashTypeSHA256
+	cd[38] = 0
+	cd[39] = codePageShift
+	be.PutUint32(cd[48:], uint32(teamOffset))
+	be.PutUint64(cd[64:], slice.uint64At(slice.text+segment64FileOffOffset))
+	be.PutUint64(cd[72:], slice.uint64At(slice.text+segment64FileSizeOffset))
+	be.PutUint64(cd[80:], execSegFlags)
+
+	copy(cd[identOffset:], options.identifier)
+	if options.teamID != "" {
+		copy(cd[teamOffset:], options.teamID)
+	}
+	for slot, hash := range specialSlots {
+		copy(cd[hashOffset-int(slot)*sha256.Size:], hash)
+	}
+	for i := 0; i < codeCount; i++ {
+		end := (i + 1) * codePageSize
+		if end > codeLimit {
+			end = codeLimit
+		}
+		hash := sha256.Sum256(slice.data[i*codePageSize : end])
+		copy(cd[hashOffset+i*sha256.Size:], hash[:])
+	}
+	return cd
+}
+
+// embeddedSignature is a parsed code signature of a Mach-O slice.
+type embeddedSignature struct {
+	blobs map[uint32][]byte
+}
+
+func parseEmbeddedSignature(data []byte) (*embeddedSignature, error) {
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != blobMagicEmbeddedSignature {
+		return nil, errors.New("invalid code signature")
+	}
+	count := int(binary.BigEndian.Uint32(data[8:]))
+	if 12+count*8 > len(data) {
+		return nil, errors.New("the code signature is truncated")
+	}
+	signature := &embeddedSignature{blobs: map[uint32][]byte{}}
+	for i := 0; i < count; i++ {
+		slot := binary.BigEndian.Uint32(data[12+i*8:])
+		offset := int(binary.BigEndian.Uint32(data[16+i*8:]))
+		if offset+8 > len(data) {
+			return nil, errors.New("the code signature is truncated")
+		}
+		length := int(binary.BigEndian.Uint32(data[offset+4:]))
+		if offset+length > len(data) {
+			return nil, errors.New("the code signature is truncated")
+		}
+		signature.blobs[slot] = data[offset : offset+length]
+	}
+	if _, ok := signature.blobs[slotCodeDirectory]; !ok {
+		return nil, errors.New("the code signature has no code directory")
+	}
+	return signature, nil
+}
+
+// cdHash returns the CDHash of the code directory.
+func (signature *embeddedSignature) cdHash() []byte {
+	hash := sha256.Sum256(signature.blobs[slotCodeDirectory])
+	return hash[:cdHashSize]
+}
+
+// verify checks the hashes of the code pages and the special slots stored
+// in the code directory. The hashes of Info.plist and CodeResources are
+// checked if given.
+func (signature *embeddedSignature) verify(slice *machOSlice, infoPlistHash, resourcesHash []byte) error {
+	cd := signature.blobs[slotCodeDirectory]
+	if len(cd) < 40 {
+		return errors.New("the code directory is truncated")
+	}
+	be := binary.BigEndian
+	hashOffset := int(be.Uint32(cd[16:]))
+	specialCount := int(be.Uint32(cd[24:]))
+	codeCount := int(be.Uint32(cd[28:]))
+	codeLimit := int(be.Uint32(cd[32:]))
+	if cd[37] != hashTypeSHA256 || cd[36] != sha256.Size || cd[39] != codePageShift {
+		return errors.New("unsupported code directory hash type")
+	}
+	if hashOffset-specialCount*sha256.Size < 0 || hashOffset+codeCount*sha256.Size > len(cd) || codeLimit > len(slice.data) {
+		return errors.New("invalid code directory")
+	}
+
+	for i := 0; i < codeCount; i++ {
+		end := (i + 1) * codePageSize
+		if end > codeLimit {
+			end = codeLimit
+		}
+		hash := sha256.Sum256(slice.data[i*codePageSize : end])
+		if !bytes.Equal(hash[:], cd[hashOffset+i*sha256.Size:hashOffset+(i+1)*sha256.Size]) {
+			return fmt.Errorf("the code page %d has been modified", i)
+		}
+	}
+
+	expected := map[uint32][]byte{slotInfoPlist: infoPlistHash, slotResources: resourcesHash}
+	for _, slot := range []uint32{slotRequirements, slotEntitlements, slotDEREntitlements} {
+		if blob, ok := signature.blobs[slot]; ok {
+			hash := sha256.Sum256(blob)
+			expected[slot] = hash[:]
+		}
+	}
+	for slot, hash := range expected {
+		if hash == nil || int(slot) > specialCount {
+			continue
+		}
+		stored := cd[hashOffset-int(slot)*sha256.Size : hashOffset-int(slot-1)*sha256.Size]
+		if !bytes.Equal(stored, hash) {
+			return fmt.Errorf("the special slot %d does not match", slot)
+		}
+	}
+	return nil
+}
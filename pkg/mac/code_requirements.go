@@ -0,0 +1,165 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	blobMagicRequirement = 0xFADE0C00
+
+	requirementTypeDesignated = 3
+	requirementExprForm       = 1
+
+	// The opcodes and the match operations of the requirement language.
+	requirementOpIdent              = 2
+	requirementOpAnchorHash         = 4
+	requirementOpAnd                = 6
+	requirementOpCertField          = 11
+	requirementOpCertGeneric        = 14
+	requirementOpAppleGenericAnchor = 15
+	requirementMatchExists          = 0
+	requirementMatchEqual           = 1
+
+	certificateSlotLeaf = 0
+)
+
+var oidAppleDeveloperIDCA = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 2, 6}
+
+// requirementTerm is a term of a requirement joined with "and", both in
+// the text and in the compiled form.
+type requirementTerm struct {
+	text string
+	code []byte
+}
+
+// designatedRequirementTerms returns the terms of the designated requirement
+// that codesign makes for the code signed with the certificate: the Team ID
+// under the Apple anchor for a Developer ID certificate, or the hash of
+// the certificate otherwise.
+func designatedRequirementTerms(identifier string, leaf *x509.Certificate) []requirementTerm {
+	terms := []requirementTerm{{
+		text: fmt.Sprintf(`identifier "%s"`, identifier),
+		code: requirementCode(requirementOpIdent, requirementData([]byte(identifier))),
+	}}
+	if hasCertificateExtension(leaf, oidDeveloperIDApplication) && len(leaf.Subject.OrganizationalUnit) != 0 {
+		teamID := leaf.Subject.OrganizationalUnit[0]
+		return append(terms,
+			requirementTerm{
+				text: "anchor apple generic",
+				code: requirementCode(requirementOpAppleGenericAnchor),
+			},
+			certificateExtensionTerm(1, "certificate 1", oidAppleDeveloperIDCA),
+			certificateExtensionTerm(certificateSlotLeaf, "certificate leaf", oidDeveloperIDApplication),
+			requirementTerm{
+				text: fmt.Sprintf(`certificate leaf[subject.OU] = "%s"`, teamID),
+				code: requirementCode(requirementOpCertField, certificateSlotLeaf, requirementData([]byte("subject.OU")),
+					requirementMatchEqual, requirementData([]byte(teamID))),
+			},
+		)
+	}
+	leafHash := sha1.Sum(leaf.Raw)
+	return append(terms, requirementTerm{
+		text: fmt.Sprintf(`certificate leaf = H"%s"`, hex.EncodeToString(leafHash[:])),
+		code: requirementCode(requirementOpAnchorHash, certificateSlotLeaf, requirementData(leafHash[:])),
+	})
+}
+
+// certificateExtensionTerm requires the certificate in the slot to have the extension.
+func certificateExtensionTerm(slot int32, name string, oid asn1.ObjectIdentifier) requirementTerm {
+	return requirementTerm{
+		text: fmt.Sprintf("%s[field.%s] /* exists */", name, oid),
+		code: requirementCode(requirementOpCertGeneric, slot, requirementData(oidContent(oid)), requirementMatchExists),
+	}
+}
+
+// oidContent returns the DER encoding of the OID without its tag and length.
+func oidContent(oid asn1.ObjectIdentifier) []byte {
+	content := []byte{}
+	for _, arc := range append([]int{oid[0]*40 + oid[1]}, oid[2:]...) {
+		chunk := []byte{byte(arc & 0x7F)}
+		for arc >>= 7; arc > 0; arc >>= 7 {
+			chunk = append([]byte{byte(arc&0x7F) | 0x80}, chunk...)
+		}
+		content = append(content, chunk...)
+	}
+	return content
+}
+
+// requirementText joins the terms of a requirement into its text.
+func requirementText(terms []requirementTerm) string {
+	texts := []string{}
+	for _, term := range terms {
+		texts = append(texts, term.text)
+	}
+	return strings.Join(texts, " and ")
+}
+
+// makeRequirementSet compiles the designated requirement of the terms into
+// a requirement set blob. The terms are joined by left-nested "and" operations.
+func makeRequirementSet(terms []requirementTerm) []byte {
+	expression := []byte{}
+	for i := 1; i < len(terms); i++ {
+		expression = append(expression, requirementCode(requirementOpAnd)...)
+	}
+	for _, term := range terms {
+		expression = append(expression, term.code...)
+	}
+	requirement := makeBlob(blobMagicRequirement, append(requirementCode(requirementExprForm), expression...))
+
+	index := make([]byte, 12)
+	binary.BigEndian.PutUint32(index, 1)
+	binary.BigEndian.PutUint32(index[4:], requirementTypeDesignated)
+	binary.BigEndian.PutUint32(index[8:], uint32(8+len(index)))
+	return makeBlob(blobMagicRequirements, append(index, requirement...))
+}
+
+// requirementCode encodes the opcode with its arguments: 32-bit integers
+// and the already encoded data.
+func requirementCode(args ...any) []byte {
+	code := []byte{}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case int:
+			code = binary.BigEndian.AppendUint32(code, uint32(v))
+		case int32:
+			code = binary.BigEndian.AppendUint32(code, uint32(v))
+		case []byte:
+			code = append(code, v...)
+		}
+	}
+	return code
+}
+
+// requirementData encodes the data argument: its length and the bytes
+// padded to 4 bytes.
+func requirementData(data []byte) []byte {
+	encoded := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	encoded = append(encoded, data...)
+	return append(encoded, make([]byte, (4-len(data)%4)%4)...)
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+// codeBundleExtensions are the extensions of the bundle directories that
+// carry their own code signature.
+var codeBundleExtensions = []string{".app", ".framework", ".xpc", ".appex", ".bundle", ".plugin"}
+
+// IsCodeBundle reports whether the path is a bundle directory that carries
+// its own code signature, judging by its extension.
+func IsCodeBundle(path string) bool {
+	return base.Contains(codeBundleExtensions, filepath.Ext(path))
+}
+
+// resourceRule is a rule of CodeResources that tells how to seal the files
+// whose paths match the pattern. The matching rule with the highest weight applies.
+type resourceRule struct {
+	pattern  string
+	weight   int64
+	omit     bool
+	optional bool
+	nested   bool
+}
+
+// The rules codesign uses by default for the version 1 and 2 seals.
+var (
+	resourceRules = []resourceRule{
+		{pattern: `^Resources/`, weight: 1},
+		{pattern: `^Resources/.*\.lproj/`, weight: 1000, optional: true},
+		{pattern: `^Resources/.*\.lproj/locversion.plist$`, weight: 1100, omit: true},
+		{pattern: `^Resources/Base\.lproj/`, weight: 1010},
+		{pattern: `^version.plist$`, weight: 1},
+	}
+	resourceRules2 = []resourceRule{
+		{pattern: `^.*`, weight: 1},
+		{pattern: `^.*\.dSYM($|/)`, weight: 11},
+		{pattern: `^(.*/)?\.DS_Store$`, weight: 2000, omit: true},
+		{pattern: `^Info\.plist$`, weight: 20, omit: true},
+		{pattern: `^PkgInfo$`, weight: 20, omit: true},
+		{pattern: `^Resources/`, weight: 20},
+		{pattern: `^Resources/.*\.lproj/`, weight: 1000, optional: true},
+		{pattern: `^Resources/.*\.lproj/locversion.plist$`, weight: 1100, omit: true},
+		{pattern: `^Resources/Base\.lproj/`, weight: 1010},
+		{pattern: `^[^/]+$`, weight: 10, nested: true},
+		{pattern: `^embedded\.provisionprofile$`, weight: 20},
+		{pattern: `^version\.plist$`, weight: 20},
+		{pattern: `^(Frameworks|SharedFrameworks|PlugIns|Plug-ins|XPCServices|Helpers|MacOS|Library/(Automator|Spotlight|LoginItems))/`, weight: 10, nested: true},
+	}
+)
+
+// resourceRulePatterns are the compiled patterns of the rules.
+var resourceRulePatterns = compileResourceRules(resourceRules, resourceRules2)
+
+func compileResourceRules(ruleSets ...[]resourceRule) map[string]*regexp.Regexp {
+	patterns := map[string]*regexp.Regexp{}
+	for _, rules := range ruleSets {
+		for _, rule := range rules {
+			patterns[rule.pattern] = regexp.MustCompile(rule.pattern)
+		}
+	}
+	return patterns
+}
+
+// matchResourceRule returns the rule with the highest weight matching
+// the path, or nil if none matches.
+func matchResourceRule(rules []resourceRule, relPath string) *resourceRule {
+	var matched *resourceRule
+	for i := range rules {
+		if resourceRulePatterns[rules[i].pattern].MatchString(relPath) && (matched == nil || rules[i].weight > matched.weight) {
+			matched = &rules[i]
+		}
+	}
+	return matched
+}
+
+// resourceRulesPlist returns the rules as stored in CodeResources.
+func resourceRulesPlist(rules []resourceRule) *plistDict {
+	dict := newPlistDict()
+	for _, rule := range rules {
+		if rule.weight == 1 && !rule.omit && !rule.optional && !rule.nested {
+			dict.set(rule.pattern, true)
+			continue
+		}
+		value := newPlistDict()
+		if rule.nested {
+			value.set("nested", true)
+		}
+		if rule.omit {
+			value.set("omit", true)
+		}
+		if rule.optional {
+			value.set("optional", true)
+		}
+		value.set("weight", float64(rule.weight))
+		dict.set(rule.pattern, value)
+	}
+	sortPlistDict(dict)
+	return dict
+}
+
+func sortPlistDict(dict *plistDict) {
+	sort.Strings(dict.keys)
+}
+
+// bundleLayout describes where the parts of a bundle are.
+type bundleLayout struct {
+	// root is the directory the resource paths are relative to:
+	// Contents for the app-like bundles, or the version directory of a framework.
+	root       string
+	infoPlist  string
+	executable string
+	identifier string
+}
+
+// readBundleLayout finds the Info.plist and the main executable of the bundle
+// or of the framework version directory at path.
+func readBundleLayout(path string) (*bundleLayout, error) {
+	if filepath.Ext(path) == ".framework" {
+		resolved, err := filepath.EvalSymlinks(filepath.Join(path, "Versions", "Current"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		path = resolved
+	}
+
+	layout := &bundleLayout{root: filepath.Join(path, "Contents")}
+	layout.infoPlist = filepath.Join(layout.root, "Info.plist")
+	executableDir := filepath.Join(layout.root, "MacOS")
+	if !base.PathExists(layout.infoPlist) {
+		layout.root = path
+		layout.infoPlist = filepath.Join(path, "Resources", "Info.plist")
+		executableDir = path
+	}
+
+	data, err := os.ReadFile(layout.infoPlist)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a bundle: %w", path, err)
+	}
+	value, err := parsePlist(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", layout.infoPlist, err)
+	}
+	info, ok := value.(*plistDict)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a dictionary", layout.infoPlist)
+	}
+	executable, _ := info.get("CFBundleExecutable").(string)
+	layout.identifier, _ = info.get("CFBundleIdentifier").(string)
+	if executable == "" || layout.identifier == "" {
+		return nil, fmt.Errorf("%s has no CFBundleExecutable or CFBundleIdentifier", layout.infoPlist)
+	}
+	layout.executable = filepath.Join(executableDir, executable)
+	return layout, nil
+}
+
+// writeCodeResources seals the resources and the nested code of the bundle
+// in _CodeSignature/CodeResources and returns the content of the file.
+func writeCodeResources(layout *bundleLayout) ([]byte, error) {
+	data, err := buildCodeResources(layout)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(layout.root, "_CodeSignature")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CodeResources"), data, 0644); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// buildCodeResources returns the CodeResources plist sealing the current
+// resources and nested code of the bundle.
+func buildCodeResources(layout *bundleLayout) ([]byte, error) {
+	files := newPlistDict()
+	files2 := newPlistDict()
+	err := filepath.WalkDir(layout.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(layout.root, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		switch {
+		case relPath == ".":
+			return nil
+		case relPath == "_CodeSignature" || relPath == "CodeResources":
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		case path == layout.executable:
+			return nil
+		}
+
+		rule2 := matchResourceRule(resourceRules2, relPath)
+		if entry.IsDir() {
+			if rule2 != nil && rule2.nested && IsCodeBundle(path) {
+				nested, err := nestedCodeEntry(path)
+				if err != nil {
+					return err
+				}
+				files2.set(relPath, nested)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rule2 == nil || rule2.omit {
+			return nil
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			symlink := newPlistDict()
+			symlink.set("symlink", target)
+			files2.set(relPath, symlink)
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if rule2.nested && IsMachOFile(path) {
+			nested, err := nestedCodeEntry(path)
+			if err != nil {
+				return err
+			}
+			files2.set(relPath, nested)
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hash1 := sha1.Sum(data)
+		hash2 := sha256.Sum256(data)
+		sealed := newPlistDict()
+		sealed.set("hash", hash1[:])
+		sealed.set("hash2", hash2[:])
+		if rule2.optional {
+			sealed.set("optional", true)
+		}
+		files2.set(relPath, sealed)
+
+		if rule := matchResourceRule(resourceRules, relPath); rule != nil && !rule.omit {
+			if rule.optional {
+				optional := newPlistDict()
+				optional.set("hash", hash1[:])
+				optional.set("optional", true)
+				files.set(relPath, optional)
+			} else {
+				files.set(relPath, hash1[:])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortPlistDict(files)
+	sortPlistDict(files2)
+
+	resources := newPlistDict()
+	resources.set("files", files)
+	resources.set("files2", files2)
+	resources.set("rules", resourceRulesPlist(resourceRules))
+	resources.set("rules2", resourceRulesPlist(resourceRules2))
+	return encodePlist(resources)
+}
+
+// nestedCodeEntry returns the CodeResources entry of the nested bundle or
+// Mach-O file: its CDHash and its designated requirement.
+func nestedCodeEntry(path string) (*plistDict, error) {
+	executable := path
+	if IsCodeBundle(path) {
+		layout, err := readBundleLayout(path)
+		if err != nil {
+			return nil, err
+		}
+		executable = layout.executable
+	}
+	file, err := readMachOFile(executable)
+	if err != nil {
+		return nil, err
+	}
+	data := file.slices[0].signature()
+	if data == nil {
+		return nil, fmt.Errorf("the nested code %s is not signed", path)
+	}
+	signature, err := parseEmbeddedSignature(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	requirement, err := signature.designatedRequirement()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	entry := newPlistDict()
+	entry.set("cdhash", signature.cdHash())
+	entry.set("requirement", requirement)
+	return entry, nil
+}
+
+// designatedRequirement returns the requirement the code satisfies as
+// long as it is signed with the same identity, in the requirement language.
+func (signature *embeddedSignature) designatedRequirement() (string, error) {
+	cd := signature.blobs[slotCodeDirectory]
+	if len(cd) < codeDirectoryHeaderSize {
+		return "", errors.New("the code directory is truncated")
+	}
+	flags := binary.BigEndian.Uint32(cd[12:])
+	if flags&codeSignFlagAdhoc != 0 {
+		return `cdhash H"` + hex.EncodeToString(signature.cdHash()) + `"`, nil
+	}
+
+	identOffset := int(binary.BigEndian.Uint32(cd[20:]))
+	if identOffset >= len(cd) {
+		return "", errors.New("the code directory identifier is out of bounds")
+	}
+	identifier := cd[identOffset:]
+	end := bytes.IndexByte(identifier, 0)
+	if end < 0 {
+		return "", errors.New("the code directory identifier is not terminated")
+	}
+	identifier = identifier[:end]
+
+	// The CMS blob starts with its magic and length.
+	cms := signature.blobs[slotSignature]
+	if len(cms) <= 8 {
+		return "", errors.New("the code signature has no CMS signature")
+	}
+	signedData, err := base.ParseSignedData(cms[8:])
+	if err != nil {
+		return "", err
+	}
+	return requirementText(designatedRequirementTerms(string(identifier), signedData.Signer)), nil
+}
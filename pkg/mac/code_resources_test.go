@@ -0,0 +1,129 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDesignatedRequirementRejectsMalformedSignature(t *testing.T) {
+	codeDirectory := func(identOffset uint32, identifier string) []byte {
+		cd := make([]byte, codeDirectoryHeaderSize)
+		binary.BigEndian.PutUint32(cd[20:], identOffset)
+		return append(cd, identifier...)
+	}
+	tests := map[string]*embeddedSignature{
+		"identifier out of bounds": {blobs: map[uint32][]byte{
+			slotCodeDirectory: codeDirectory(1000, "app\x00"),
+			slotSignature:     make([]byte, 64),
+		}},
+		"identifier not terminated": {blobs: map[uint32][]byte{
+			slotCodeDirectory: codeDirectory(codeDirectoryHeaderSize, "app"),
+			slotSignature:     make([]byte, 64),
+		}},
+		"no CMS signature": {blobs: map[uint32][]byte{
+			slotCodeDirectory: codeDirectory(codeDirectoryHeaderSize, "app\x00"),
+		}},
+		"empty CMS signature": {blobs: map[uint32][]byte{
+			slotCodeDirectory: codeDirectory(codeDirectoryHeaderSize, "app\x00"),
+			slotSignature:     makeBlob(blobMagicBlobWrapper, nil),
+		}},
+	}
+	for name, signature := range tests {
+		if _, err := signature.designatedRequirement(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+const testInfoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleExecutable</key>
+	<string>Test</string>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.test</string>
+</dict>
+</plist>
+`
+
+// writeTestBundle writes Test.app with resources and a nested dylib to dir
+// and returns the path to the bundle.
+func writeTestBundle(t *testing.T, dir string) string {
+	t.Helper()
+	app := filepath.Join(dir, "Test.app")
+	contents := filepath.Join(app, "Contents")
+	files := map[string][]byte{
+		"Info.plist":                             []byte(testInfoPlist),
+		"PkgInfo":                                []byte("APPL????"),
+		"MacOS/Test":                             testMachO(cpuTypeARM64),
+		"Frameworks/libtest.dylib":               testMachO(cpuTypeX86_64),
+		"Resources/app.icns":                     []byte("icon"),
+		"Resources/.DS_Store":                    []byte("omitted"),
+		"Resources/Base.lproj/Main.strings":      []byte("\"key\" = \"base\";"),
+		"Resources/en.lproj/Localizable.strings": []byte("\"key\" = \"value\";"),
+		"Resources/en.lproj/locversion.plist":    []byte("omitted"),
+	}
+	for relPath, data := range files {
+		writeTestFile(t, filepath.Join(contents, filepath.FromSlash(relPath)), data)
+	}
+	return app
+}
+
+func TestBuildCodeResourcesMatchesGolden(t *testing.T) {
+	app := writeTestBundle(t, t.TempDir())
+	if err := os.Symlink("app.icns", filepath.Join(app, "Contents", "Resources", "link.icns")); err != nil {
+		t.Skipf("cannot create a symlink: %v", err)
+	}
+	if err := (&goCodeSigner{}).sign(filepath.Join(app, "Contents", "Frameworks", "libtest.dylib"), ""); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := readBundleLayout(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, err := buildCodeResources(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "CodeResources")
+	if *updateGolden {
+		if err := os.WriteFile(golden, resources, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(resources, expected) {
+		t.Errorf("CodeResources differs from %s:\n%s", golden, resources)
+	}
+}
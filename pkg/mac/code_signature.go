@@ -0,0 +1,105 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+const (
+	machOMagic32 = 0xFEEDFACE
+	machOMagic64 = 0xFEEDFACF
+	fatMagic     = 0xCAFEBABE
+	fatMagic64   = 0xCAFEBABF
+
+	// maxFatArchitectures tells a universal binary from a Java class file,
+	// which starts with the same magic followed by the class file version.
+	maxFatArchitectures = 20
+)
+
+// IsMachOFile reports whether the file is a Mach-O binary or a universal
+// binary judging by its magic number.
+func IsMachOFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	switch binary.BigEndian.Uint32(header) {
+	case machOMagic32, machOMagic64:
+		return true
+	case fatMagic, fatMagic64:
+		return binary.BigEndian.Uint32(header[4:]) <= maxFatArchitectures
+	}
+	switch binary.LittleEndian.Uint32(header) {
+	case machOMagic32, machOMagic64:
+		return true
+	}
+	return false
+}
+
+// ValidSignerNames returns the signing authority (e.g. "Developer ID Application:
+// Name (TEAMID)") and the Team ID of the code signature of the file or bundle, or nil if it is not signed
+// or signed ad hoc.
+//
+// Returns an error if the signature is invalid.
+func ValidSignerNames(path string) ([]string, error) {
+	output, err := base.ExecCommandAndGetOutput("codesign", []string{"-dvv", path})
+	if err != nil {
+		if strings.Contains(output, "not signed at all") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// The first authority is the leaf certificate, the others are its issuers.
+	names := []string{}
+	hasAuthority := false
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || value == "not set" {
+			continue
+		}
+		if key == "TeamIdentifier" || (key == "Authority" && !hasAuthority) {
+			names = append(names, value)
+			hasAuthority = hasAuthority || key == "Authority"
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if err := base.ExecCommand("codesign", []string{"--verify", "--strict", path}); err != nil {
+		return nil, fmt.Errorf("%s: invalid code signature: %w", path, err)
+	}
+	return names, nil
+}
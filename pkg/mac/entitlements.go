@@ -0,0 +1,244 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	keychainAccessGroupsEntitlement = "keychain-access-groups"
+
+	// defaultEntitlementsKey selects the entitlements of the nested code
+	// other than the helper bundles listed in entitlementsKeys.
+	defaultEntitlementsKey = "default"
+)
+
+// entitlementsKeys maps the keys of `mac.entitlements` to the bundle types.
+var entitlementsKeys = map[string]CrBundleType{
+	"main":     CrBundleMain,
+	"helper":   CrBundleHelper,
+	"alerts":   CrBundleHelperAlerts,
+	"gpu":      CrBundleHelperGPU,
+	"plugin":   CrBundleHelperPlugin,
+	"renderer": CrBundleHelperRenderer,
+}
+
+// SigningEntitlements selects the entitlements file for every piece of
+// code in the app bundle.
+//
+// The main bundle is signed with `mac.entitlements.main`, or with
+// `mac.codesignEntitlements` if not set. A helper bundle is signed with
+// the entry of its type, and the rest of the nested code with the "default"
+// entry. Both fall back to `mac.codesignEntitlements`. Since only the main
+// bundle has the provisioning profile, keychain-access-groups is removed
+// from the entitlements of the nested code.
+type SigningEntitlements struct {
+	app       string
+	helpers   map[CrBundleType]string
+	nested    string
+	tempFiles []string
+}
+
+// PrepareEntitlements validates `mac.entitlements` and writes the copies of
+// the entitlements files without keychain-access-groups for the nested code.
+// Call Remove to delete the copies when signing is done.
+func PrepareEntitlements(params common.BrandingParams) (*SigningEntitlements, error) {
+	entitlements := &SigningEntitlements{
+		app:     params.Mac.CodesignEntitlements,
+		helpers: map[CrBundleType]string{},
+		nested:  params.Mac.CodesignEntitlements,
+	}
+	configured, err := configuredEntitlements(params)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(configured))
+	for key := range configured {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// The stripped copies of the files used for the nested code.
+	nestedFiles := map[string]string{}
+	nestedFile := func(path string) (string, error) {
+		if stripped, ok := nestedFiles[path]; ok {
+			return stripped, nil
+		}
+		stripped, err := entitlements.withoutKeychainAccessGroups(path)
+		if err != nil {
+			return "", err
+		}
+		nestedFiles[path] = stripped
+		return stripped, nil
+	}
+
+	for _, key := range keys {
+		path := configured[key]
+		if _, err := os.Stat(path); err != nil {
+			entitlements.Remove()
+			return nil, fmt.Errorf("entitlements for %q: %w", key, err)
+		}
+		if key == defaultEntitlementsKey {
+			entitlements.nested = path
+			continue
+		}
+		bundleType := entitlementsKeys[key]
+		if bundleType == CrBundleMain {
+			entitlements.app = path
+			continue
+		}
+		stripped, err := nestedFile(path)
+		if err != nil {
+			entitlements.Remove()
+			return nil, err
+		}
+		entitlements.helpers[bundleType] = stripped
+	}
+
+	stripped, err := nestedFile(entitlements.nested)
+	if err != nil {
+		entitlements.Remove()
+		return nil, err
+	}
+	entitlements.nested = stripped
+	return entitlements, nil
+}
+
+// configuredEntitlements returns `mac.entitlements` with the keys in lower case.
+// Returns an error if a key is unknown or is given twice in different cases.
+func configuredEntitlements(params common.BrandingParams) (map[string]string, error) {
+	configured := map[string]string{}
+	for key, path := range params.Mac.Entitlements {
+		normalized := strings.ToLower(key)
+		if _, ok := entitlementsKeys[normalized]; !ok && normalized != defaultEntitlementsKey {
+			return nil, fmt.Errorf("unknown mac.entitlements key %q, expected one of main, helper, alerts, gpu, plugin, renderer, or default", key)
+		}
+		if _, ok := configured[normalized]; ok {
+			return nil, fmt.Errorf("mac.entitlements key %q is given more than once", normalized)
+		}
+		configured[normalized] = path
+	}
+	return configured, nil
+}
+
+// App returns the entitlements file of the main bundle.
+func (entitlements *SigningEntitlements) App() string {
+	return entitlements.app
+}
+
+// For returns the entitlements file of the nested code at the path.
+func (entitlements *SigningEntitlements) For(path string) string {
+	if bundleType, ok := helperBundleType(path); ok {
+		if helper, ok := entitlements.helpers[bundleType]; ok {
+			return helper
+		}
+	}
+	return entitlements.nested
+}
+
+// AppHasKeychainAccessGroups reports whether the entitlements of the main
+// bundle contain keychain-access-groups, which requires a provisioning profile.
+func (entitlements *SigningEntitlements) AppHasKeychainAccessGroups() (bool, error) {
+	dict, err := readEntitlements(entitlements.app)
+	if err != nil {
+		return false, err
+	}
+	return dict.has(keychainAccessGroupsEntitlement), nil
+}
+
+// Remove deletes the temporary entitlements files.
+func (entitlements *SigningEntitlements) Remove() {
+	for _, file := range entitlements.tempFiles {
+		_ = os.Remove(file)
+	}
+	entitlements.tempFiles = nil
+}
+
+// withoutKeychainAccessGroups returns the path to the entitlements file, or
+// to its temporary copy without keychain-access-groups if the file has it.
+func (entitlements *SigningEntitlements) withoutKeychainAccessGroups(path string) (string, error) {
+	dict, err := readEntitlements(path)
+	if err != nil {
+		return "", err
+	}
+	if !dict.has(keychainAccessGroupsEntitlement) {
+		return path, nil
+	}
+	dict.remove(keychainAccessGroupsEntitlement)
+	data, err := encodePlist(dict)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "helper-entitlements-*.plist")
+	if err != nil {
+		return "", fmt.Errorf("creating temp entitlements: %w", err)
+	}
+	entitlements.tempFiles = append(entitlements.tempFiles, file.Name())
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return "", fmt.Errorf("writing temp entitlements: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// readEntitlements reads the entitlements plist, which must be a dictionary.
+func readEntitlements(path string) (*plistDict, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading entitlements %s: %w", path, err)
+	}
+	value, err := parsePlist(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing entitlements %s: %w", path, err)
+	}
+	dict, ok := value.(*plistDict)
+	if !ok {
+		return nil, fmt.Errorf("the entitlements %s are not a dictionary", path)
+	}
+	return dict, nil
+}
+
+// helperBundleType returns the type of the helper bundle at the path judging
+// by its name, e.g. "My App Helper (Renderer).app".
+func helperBundleType(path string) (CrBundleType, bool) {
+	name := filepath.Base(path)
+	if filepath.Ext(name) != ".app" {
+		return 0, false
+	}
+	name = strings.TrimSuffix(name, ".app")
+	if strings.HasSuffix(name, " Helper") {
+		return CrBundleHelper, true
+	}
+	for i, typeName := range crHelperTypeNames {
+		if strings.HasSuffix(name, " Helper ("+typeName+")") {
+			return CrBundleType(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The DER tags of the entitlements: the [APPLICATION 16] envelope,
+// the [CONTEXT 16] dictionaries, the sequences, and the strings.
+const (
+	derEntitlementsTag = 0x70
+	derDictionaryTag   = 0xB0
+	derSequenceTag     = 0x30
+	derUTF8StringTag   = 0x0C
+)
+
+// encodeEntitlementsDER converts the entitlements plist into the DER form
+// the kernel reads on macOS 12 and later: a version and the dictionary
+// of the entitlements.
+func encodeEntitlementsDER(entitlements []byte) ([]byte, error) {
+	value, err := parsePlist(entitlements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entitlements: %w", err)
+	}
+	if _, ok := value.(*plistDict); !ok {
+		return nil, errors.New("invalid entitlements: the root element is not a dict")
+	}
+	dict, err := encodeEntitlementDER(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entitlements: %w", err)
+	}
+	version, err := asn1.Marshal(1)
+	if err != nil {
+		return nil, err
+	}
+	return derElement(derEntitlementsTag, append(version, dict...)), nil
+}
+
+// encodeEntitlementDER encodes an entitlement value. The dictionary entries
+// are sorted by their keys, as the kernel requires.
+func encodeEntitlementDER(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case *plistDict:
+		keys := append([]string(nil), v.keys...)
+		sort.Strings(keys)
+		entries := []byte{}
+		for _, key := range keys {
+			item, err := encodeEntitlementDER(v.values[key])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			entries = append(entries, derElement(derSequenceTag, append(derElement(derUTF8StringTag, []byte(key)), item...))...)
+		}
+		return derElement(derDictionaryTag, entries), nil
+	case []any:
+		items := []byte{}
+		for _, item := range v {
+			encoded, err := encodeEntitlementDER(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, encoded...)
+		}
+		return derElement(derSequenceTag, items), nil
+	case string:
+		return derElement(derUTF8StringTag, []byte(v)), nil
+	case bool, int64:
+		return asn1.Marshal(v)
+	default:
+		return nil, fmt.Errorf("unsupported entitlement value of type %T", value)
+	}
+}
+
+// derElement encodes the content with the single-byte tag and the DER length.
+func derElement(tag byte, content []byte) []byte {
+	element := []byte{tag}
+	if len(content) < 0x80 {
+		element = append(element, byte(len(content)))
+	} else {
+		length := []byte{}
+		for n := len(content); n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		element = append(append(element, 0x80|byte(len(length))), length...)
+	}
+	return append(element, content...)
+}
@@ -0,0 +1,401 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+type entitlementKind int
+
+const (
+	booleanEntitlement entitlementKind = iota
+	stringEntitlement
+	arrayEntitlement
+)
+
+func (kind entitlementKind) String() string {
+	switch kind {
+	case booleanEntitlement:
+		return "a boolean"
+	case stringEntitlement:
+		return "a string"
+	default:
+		return "an array of strings"
+	}
+}
+
+// entitlementSpec describes a known entitlement.
+type entitlementSpec struct {
+	kind entitlementKind
+
+	// exception tells whether the entitlement is a hardened runtime exception.
+	exception bool
+
+	// needsProfile tells whether the entitlement must be allowed by
+	// a provisioning profile.
+	needsProfile bool
+}
+
+const (
+	allowJITEntitlement                      = "com.apple.security.cs.allow-jit"
+	allowUnsignedExecutableMemoryEntitlement = "com.apple.security.cs.allow-unsigned-executable-memory"
+	disableLibraryValidationEntitlement      = "com.apple.security.cs.disable-library-validation"
+	getTaskAllowEntitlement                  = "com.apple.security.get-task-allow"
+
+	// maxMisspellingDistance is the maximum number of the edits that turn
+	// an unknown key into a known one for the key to be deemed misspelled.
+	maxMisspellingDistance = 3
+)
+
+// knownEntitlements are the entitlements of the apps distributed outside
+// the Mac App Store.
+var knownEntitlements = map[string]entitlementSpec{
+	allowJITEntitlement:                                        {kind: booleanEntitlement, exception: true},
+	allowUnsignedExecutableMemoryEntitlement:                   {kind: booleanEntitlement, exception: true},
+	"com.apple.security.cs.allow-dyld-environment-variables":   {kind: booleanEntitlement, exception: true},
+	disableLibraryValidationEntitlement:                        {kind: booleanEntitlement, exception: true},
+	"com.apple.security.cs.disable-executable-page-protection": {kind: booleanEntitlement, exception: true},
+	"com.apple.security.cs.debugger":                           {kind: booleanEntitlement, exception: true},
+	getTaskAllowEntitlement:                                    {kind: booleanEntitlement},
+	"com.apple.security.automation.apple-events":               {kind: booleanEntitlement},
+	"com.apple.security.device.audio-input":                    {kind: booleanEntitlement},
+	"com.apple.security.device.camera":                         {kind: booleanEntitlement},
+	"com.apple.security.device.bluetooth":                      {kind: booleanEntitlement},
+	"com.apple.security.device.usb":                            {kind: booleanEntitlement},
+	"com.apple.security.device.print":                          {kind: booleanEntitlement},
+	"com.apple.security.personal-information.location":         {kind: booleanEntitlement},
+	"com.apple.security.personal-information.addressbook":      {kind: booleanEntitlement},
+	"com.apple.security.personal-information.calendars":        {kind: booleanEntitlement},
+	"com.apple.security.personal-information.photos-library":   {kind: booleanEntitlement},
+	"com.apple.security.app-sandbox":                           {kind: booleanEntitlement},
+	"com.apple.security.network.client":                        {kind: booleanEntitlement},
+	"com.apple.security.network.server":                        {kind: booleanEntitlement},
+	"com.apple.security.files.user-selected.read-only":         {kind: booleanEntitlement},
+	"com.apple.security.files.user-selected.read-write":        {kind: booleanEntitlement},
+	"com.apple.security.files.downloads.read-write":            {kind: booleanEntitlement},
+	"com.apple.security.application-groups":                    {kind: arrayEntitlement},
+	keychainAccessGroupsEntitlement:                            {kind: arrayEntitlement, needsProfile: true},
+	applicationIdentifierEntitlement:                           {kind: stringEntitlement, needsProfile: true},
+	"com.apple.developer.team-identifier":                      {kind: stringEntitlement, needsProfile: true},
+	"com.apple.developer.associated-domains":                   {kind: arrayEntitlement, needsProfile: true},
+	"com.apple.developer.web-browser.public-key-credential":    {kind: booleanEntitlement, needsProfile: true},
+	"com.apple.developer.aps-environment":                      {kind: stringEntitlement, needsProfile: true},
+	"com.apple.developer.icloud-container-identifiers":         {kind: arrayEntitlement, needsProfile: true},
+	"com.apple.developer.icloud-services":                      {kind: arrayEntitlement, needsProfile: true},
+	"com.apple.developer.networking.networkextension":          {kind: arrayEntitlement, needsProfile: true},
+	"com.apple.developer.usernotifications.time-sensitive":     {kind: booleanEntitlement, needsProfile: true},
+}
+
+// recommendedExceptions are the hardened runtime exceptions Chromium needs
+// for every key of `mac.entitlements`: the renderer and GPU processes run
+// JIT-compiled code, and the plug-in processes load the third-party code.
+var recommendedExceptions = map[string][]string{
+	"main":     {},
+	"helper":   {},
+	"alerts":   {},
+	"gpu":      {allowJITEntitlement},
+	"plugin":   {allowUnsignedExecutableMemoryEntitlement, disableLibraryValidationEntitlement},
+	"renderer": {allowJITEntitlement},
+	"default":  {},
+}
+
+// entitlementsTargetKeys are the keys of `mac.entitlements` in the order
+// the entitlements are reported.
+var entitlementsTargetKeys = []string{"main", "helper", "alerts", "gpu", "plugin", "renderer", defaultEntitlementsKey}
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+type entitlementsIssue struct {
+	severity string
+	key      string
+	message  string
+}
+
+// EntitlementsReport lists the problems of an entitlements file used for
+// the code of the given `mac.entitlements` keys.
+type EntitlementsReport struct {
+	Path    string
+	Targets []string
+
+	issues       []entitlementsIssue
+	entitlements *plistDict
+}
+
+// LintEntitlements checks every entitlements file configured in the parameters:
+// the keys must be known and have values of the right types, the hardened
+// runtime exceptions should be needed by Chromium for the code they are used
+// for, and the keys allowed by a provisioning profile are reported.
+func LintEntitlements(params common.BrandingParams) ([]*EntitlementsReport, error) {
+	configured, err := configuredEntitlements(params)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []*EntitlementsReport{}
+	byPath := map[string]*EntitlementsReport{}
+	for _, target := range entitlementsTargetKeys {
+		path, ok := configured[target]
+		if !ok {
+			path = params.Mac.CodesignEntitlements
+		}
+		if path == "" {
+			continue
+		}
+		report, ok := byPath[path]
+		if !ok {
+			entitlements, err := readEntitlements(path)
+			if err != nil {
+				return nil, err
+			}
+			report = &EntitlementsReport{Path: path, entitlements: entitlements}
+			byPath[path] = report
+			reports = append(reports, report)
+		}
+		report.Targets = append(report.Targets, target)
+	}
+
+	for _, report := range reports {
+		report.lint(params)
+	}
+	return reports, nil
+}
+
+func (report *EntitlementsReport) lint(params common.BrandingParams) {
+	for _, key := range report.entitlements.keys {
+		value := report.entitlements.get(key)
+		spec, known := knownEntitlements[key]
+		if !known {
+			if suggestion, distance := closestEntitlement(key); distance <= maxMisspellingDistance {
+				report.add(severityError, key, "unknown entitlement, did you mean "+suggestion+"?")
+			} else {
+				report.add(severityWarning, key, "unknown entitlement")
+			}
+			continue
+		}
+		if !hasEntitlementKind(value, spec.kind) {
+			report.add(severityError, key, "the value must be "+spec.kind.String())
+			continue
+		}
+		if value == false {
+			report.add(severityWarning, key, "the value is false and has no effect")
+			continue
+		}
+		if key == getTaskAllowEntitlement {
+			report.add(severityError, key, "allows debugging the app, and the app with it cannot be notarized")
+			continue
+		}
+		if spec.exception {
+			if targets := report.targetsNotNeeding(key); len(targets) != 0 {
+				report.add(severityWarning, key, "the hardened runtime exception is not needed by Chromium for "+strings.Join(targets, ", "))
+			}
+		}
+		if spec.needsProfile {
+			report.lintProfileEntitlement(key, params)
+		}
+	}
+}
+
+func (report *EntitlementsReport) lintProfileEntitlement(key string, params common.BrandingParams) {
+	if !base.Contains(report.Targets, "main") {
+		if key != keychainAccessGroupsEntitlement {
+			report.add(severityWarning, key, "requires a provisioning profile, which only the app bundle has")
+		}
+		return
+	}
+	if params.Mac.ProvisioningProfile == "" {
+		report.add(severityWarning, key, "requires a provisioning profile, set mac.provisioningProfile")
+	} else {
+		report.add(severityInfo, key, "must be allowed by the provisioning profile")
+	}
+	if key == keychainAccessGroupsEntitlement && len(report.Targets) > 1 {
+		report.add(severityInfo, key, "removed from the entitlements of the nested code")
+	}
+}
+
+func (report *EntitlementsReport) add(severity, key, message string) {
+	report.issues = append(report.issues, entitlementsIssue{severity, key, message})
+}
+
+// targetsNotNeeding returns the targets of the report that do not need
+// the hardened runtime exception.
+func (report *EntitlementsReport) targetsNotNeeding(exception string) []string {
+	targets := []string{}
+	for _, target := range report.Targets {
+		if !base.Contains(recommendedExceptions[target], exception) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// HasErrors reports whether the entitlements have errors that make
+// the app misbehave or fail notarization.
+func (report *EntitlementsReport) HasErrors() bool {
+	for _, issue := range report.issues {
+		if issue.severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary lists the errors and the warnings, or returns an empty string
+// if there are none.
+func (report *EntitlementsReport) Summary() string {
+	var text strings.Builder
+	for _, issue := range report.issues {
+		if issue.severity != severityInfo {
+			fmt.Fprintf(&text, "  %s: %s: %s\n", issue.severity, issue.key, issue.message)
+		}
+	}
+	if text.Len() == 0 {
+		return ""
+	}
+	return report.title() + text.String()
+}
+
+// String lists all the issues and compares the hardened runtime exceptions
+// with the recommended ones for every target.
+func (report *EntitlementsReport) String() string {
+	var text strings.Builder
+	text.WriteString(report.title())
+	for _, issue := range report.issues {
+		fmt.Fprintf(&text, "  %s: %s: %s\n", issue.severity, issue.key, issue.message)
+	}
+	for _, target := range report.Targets {
+		text.WriteString(report.exceptionsDiff(target))
+	}
+	return text.String()
+}
+
+func (report *EntitlementsReport) title() string {
+	return fmt.Sprintf("Entitlements %s (%s):\n", report.Path, strings.Join(report.Targets, ", "))
+}
+
+// exceptionsDiff compares the hardened runtime exceptions of the file with
+// the recommended ones for the target: the lines starting with "-" are
+// the exceptions to remove, and with "+" the ones to add.
+func (report *EntitlementsReport) exceptionsDiff(target string) string {
+	present := []string{}
+	for _, key := range report.entitlements.keys {
+		if knownEntitlements[key].exception && report.entitlements.get(key) == true {
+			present = append(present, key)
+		}
+	}
+	recommended := recommendedExceptions[target]
+
+	lines := []string{}
+	for _, key := range present {
+		if base.Contains(recommended, key) {
+			lines = append(lines, "    "+key)
+		} else {
+			lines = append(lines, "  - "+key)
+		}
+	}
+	for _, key := range recommended {
+		if !base.Contains(present, key) {
+			lines = append(lines, "  + "+key)
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i][4:] < lines[j][4:] })
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "  Hardened runtime exceptions compared with the recommended ones for %s:\n", target)
+	if len(lines) == 0 {
+		text.WriteString("    none\n")
+	}
+	for _, line := range lines {
+		text.WriteString("  " + line + "\n")
+	}
+	return text.String()
+}
+
+func hasEntitlementKind(value any, kind entitlementKind) bool {
+	switch kind {
+	case booleanEntitlement:
+		_, ok := value.(bool)
+		return ok
+	case stringEntitlement:
+		_, ok := value.(string)
+		return ok
+	default:
+		items, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// closestEntitlement returns the known entitlement with the fewest edits
+// from the key, and the number of the edits.
+func closestEntitlement(key string) (string, int) {
+	closest, minDistance := "", -1
+	for known := range knownEntitlements {
+		distance := editDistance(key, known)
+		if minDistance < 0 || distance < minDistance || (distance == minDistance && known < closest) {
+			closest, minDistance = known, distance
+		}
+	}
+	return closest, minDistance
+}
+
+// editDistance returns the Levenshtein distance between the strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
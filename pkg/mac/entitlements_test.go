@@ -0,0 +1,86 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const testEntitlements = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>com.apple.security.cs.allow-jit</key>
+	<true/>
+</dict>
+</plist>
+`
+
+func TestEntitlementsKeysAreCaseInsensitive(t *testing.T) {
+	dir := t.TempDir()
+	appPath := filepath.Join(dir, "app.plist")
+	defaultPath := filepath.Join(dir, "default.plist")
+	for _, path := range []string{appPath, defaultPath} {
+		if err := os.WriteFile(path, []byte(testEntitlements), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	params := common.BrandingParams{Mac: common.Mac{
+		CodesignEntitlements: appPath,
+		Entitlements:         map[string]string{"Default": defaultPath},
+	}}
+
+	entitlements, err := PrepareEntitlements(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entitlements.Remove()
+	if nested := entitlements.For("Contents/Frameworks/lib.dylib"); nested == entitlements.App() {
+		t.Errorf("the nested code is signed with the app entitlements %s", nested)
+	}
+
+	reports, err := LintEntitlements(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, report := range reports {
+		if report.Path == defaultPath {
+			return
+		}
+	}
+	t.Errorf("the default entitlements are not linted")
+}
+
+func TestEntitlementsKeysMustBeUnique(t *testing.T) {
+	params := common.BrandingParams{Mac: common.Mac{
+		Entitlements: map[string]string{"Main": "a.plist", "main": "b.plist"},
+	}}
+	if _, err := PrepareEntitlements(params); err == nil {
+		t.Error("the duplicate key is accepted")
+	}
+	if _, err := LintEntitlements(params); err == nil {
+		t.Error("the duplicate key is accepted by the linter")
+	}
+}
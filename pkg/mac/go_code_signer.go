@@ -0,0 +1,326 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	codesignToolCodesign = "codesign"
+	codesignToolGo       = "go"
+
+	adhocIdentity       = "-"
+	defaultTimestampURL = "http://timestamp.apple.com/ts01"
+	noTimestamp         = "none"
+
+	// cmsReserve is the room reserved for the CMS signature besides
+	// the certificates, and timestampReserve for the timestamp token.
+	cmsReserve       = 4096
+	timestampReserve = 12288
+)
+
+var (
+	oidDeveloperIDApplication = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 1, 13}
+	oidCDHashesPlist          = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 9, 1}
+	oidCDHashes               = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 9, 2}
+	oidSHA256                 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+)
+
+// goCodeSigner signs Mach-O files and bundles without codesign, either
+// ad hoc or with the certificate and the private key of a PKCS #12 file.
+type goCodeSigner struct {
+	certificate  *x509.Certificate
+	key          crypto.Signer
+	chain        []*x509.Certificate
+	teamID       string
+	timestampURL string
+}
+
+// UsesGoCodeSigner tells whether the binaries are signed in Go rather than
+// with codesign: if `mac.codesignTool` is "go", or if it is not set and
+// a PKCS #12 file is given or the tool does not run on macOS.
+func UsesGoCodeSigner(params common.BrandingParams) (bool, error) {
+	switch params.Mac.CodesignTool {
+	case codesignToolGo:
+		return true, nil
+	case codesignToolCodesign:
+		return false, nil
+	case "":
+		return params.Mac.CodesignCertificate != "" || runtime.GOOS != "darwin", nil
+	default:
+		return false, fmt.Errorf("unknown mac.codesignTool %q, expected %s or %s", params.Mac.CodesignTool, codesignToolCodesign, codesignToolGo)
+	}
+}
+
+// IsSigningConfigured reports whether the identity to sign the app bundle
+// with is set: a keychain identity, "-" for ad-hoc signing, or a PKCS #12 file.
+func IsSigningConfigured(params common.BrandingParams) bool {
+	return base.GetValue(params.Mac.CodesignIdentity) != "" || params.Mac.CodesignCertificate != ""
+}
+
+// newGoCodeSigner loads the PKCS #12 file from `mac.codesignCertificate`,
+// or creates an ad-hoc signer if the identity is "-".
+func newGoCodeSigner(params common.BrandingParams) (*goCodeSigner, error) {
+	if params.Mac.CodesignCertificate == "" {
+		if base.GetValue(params.Mac.CodesignIdentity) != adhocIdentity {
+			return nil, errors.New("signing without codesign needs mac.codesignCertificate, or \"-\" as mac.codesignIdentity for ad-hoc signing")
+		}
+		return &goCodeSigner{}, nil
+	}
+
+	data, err := os.ReadFile(params.Mac.CodesignCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("reading the signing certificate: %w", err)
+	}
+	key, certificate, chain, err := pkcs12.DecodeChain(data, base.GetValue(params.Mac.CodesignCertificatePassword))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", params.Mac.CodesignCertificate, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T in %s", key, params.Mac.CodesignCertificate)
+	}
+
+	codeSigner := &goCodeSigner{
+		certificate:  certificate,
+		key:          signer,
+		chain:        chain,
+		timestampURL: defaultTimestampURL,
+	}
+	if len(certificate.Subject.OrganizationalUnit) != 0 {
+		codeSigner.teamID = certificate.Subject.OrganizationalUnit[0]
+	}
+	switch params.Mac.TimestampUrl {
+	case "":
+	case noTimestamp:
+		codeSigner.timestampURL = ""
+	default:
+		codeSigner.timestampURL = params.Mac.TimestampUrl
+	}
+	return codeSigner, nil
+}
+
+// sign signs the bundle, the framework version directory, or the Mach-O
+// file at path with the hardened runtime and the given entitlements file.
+func (signer *goCodeSigner) sign(path, entitlementsPath string) error {
+	var entitlements []byte
+	if entitlementsPath != "" {
+		data, err := os.ReadFile(entitlementsPath)
+		if err != nil {
+			return fmt.Errorf("reading entitlements: %w", err)
+		}
+		entitlements = data
+	}
+	options := codeSignatureOptions{
+		teamID:       signer.teamID,
+		adhoc:        signer.certificate == nil,
+		runtime:      true,
+		entitlements: entitlements,
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		options.identifier = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		return signer.signMachO(path, options)
+	}
+
+	layout, err := readBundleLayout(path)
+	if err != nil {
+		return err
+	}
+	resources, err := writeCodeResources(layout)
+	if err != nil {
+		return err
+	}
+	infoPlist, err := os.ReadFile(layout.infoPlist)
+	if err != nil {
+		return err
+	}
+	infoPlistHash := sha256.Sum256(infoPlist)
+	resourcesHash := sha256.Sum256(resources)
+	options.identifier = layout.identifier
+	options.infoPlistHash = infoPlistHash[:]
+	options.resourcesHash = resourcesHash[:]
+	return signer.signMachO(layout.executable, options)
+}
+
+func (signer *goCodeSigner) signMachO(path string, options codeSignatureOptions) error {
+	file, err := readMachOFile(path)
+	if err != nil {
+		return err
+	}
+	var sign cmsSignFunc
+	cmsSize := 0
+	if signer.certificate != nil {
+		options.requirements = makeRequirementSet(designatedRequirementTerms(options.identifier, signer.certificate))
+		sign = signer.signCodeDirectory
+		cmsSize = cmsReserve + len(signer.certificate.Raw)
+		for _, certificate := range signer.chain {
+			cmsSize += len(certificate.Raw)
+		}
+		if signer.timestampURL != "" {
+			cmsSize += timestampReserve
+		}
+	}
+	for _, slice := range file.slices {
+		if _, err := signSlice(slice, options, sign, cmsSize); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, file.bytes(), info.Mode())
+}
+
+// signCodeDirectory creates the CMS signature of the code directory with
+// the attributes listing its CDHash, as codesign does.
+func (signer *goCodeSigner) signCodeDirectory(codeDirectory []byte) ([]byte, error) {
+	hash := sha256.Sum256(codeDirectory)
+	cdHashes := newPlistDict()
+	cdHashes.set("cdhashes", []any{hash[:cdHashSize]})
+	cdHashesPlist, err := encodePlist(cdHashes)
+	if err != nil {
+		return nil, err
+	}
+	cdHashesPlistValue, err := asn1.Marshal(cdHashesPlist)
+	if err != nil {
+		return nil, err
+	}
+	cdHashesValue, err := asn1.Marshal(struct {
+		Algorithm asn1.ObjectIdentifier
+		Digest    []byte
+	}{oidSHA256, hash[:]})
+	if err != nil {
+		return nil, err
+	}
+
+	cmsSigner := base.CMSSigner{
+		Certificate:  signer.certificate,
+		Key:          signer.key,
+		Chain:        signer.chain,
+		TimestampURL: signer.timestampURL,
+	}
+	return cmsSigner.SignDetached(codeDirectory, []base.CMSAttribute{
+		{Type: oidCDHashesPlist, Value: cdHashesPlistValue},
+		{Type: oidCDHashes, Value: cdHashesValue},
+	})
+}
+
+// verify checks the signature of the bundle, the framework version directory,
+// or the Mach-O file at path: the hashes of the code and the sealed
+// resources, and the CMS signature of the code directory.
+func (signer *goCodeSigner) verify(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return verifyMachOSignature(path, nil, nil)
+	}
+
+	layout, err := readBundleLayout(path)
+	if err != nil {
+		return err
+	}
+	stored, err := os.ReadFile(filepath.Join(layout.root, "_CodeSignature", "CodeResources"))
+	if err != nil {
+		return fmt.Errorf("%s is not signed: %w", path, err)
+	}
+	infoPlist, err := os.ReadFile(layout.infoPlist)
+	if err != nil {
+		return err
+	}
+	infoPlistHash := sha256.Sum256(infoPlist)
+	resourcesHash := sha256.Sum256(stored)
+	if err := verifyMachOSignature(layout.executable, infoPlistHash[:], resourcesHash[:]); err != nil {
+		return err
+	}
+
+	current, err := buildCodeResources(layout)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, stored) {
+		return fmt.Errorf("%s: the sealed resources have been modified", path)
+	}
+	return nil
+}
+
+func verifyMachOSignature(path string, infoPlistHash, resourcesHash []byte) error {
+	file, err := readMachOFile(path)
+	if err != nil {
+		return err
+	}
+	for _, slice := range file.slices {
+		data := slice.signature()
+		if data == nil {
+			return fmt.Errorf("%s is not signed", path)
+		}
+		signature, err := parseEmbeddedSignature(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := signature.verify(slice, infoPlistHash, resourcesHash); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if cms := signature.blobs[slotSignature]; len(cms) > 8 {
+			signedData, err := base.ParseSignedData(cms[8:])
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			signedData.Content = signature.blobs[slotCodeDirectory]
+			if err := signedData.VerifySignature(); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func hasCertificateExtension(certificate *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,508 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	cpuTypeX86_64 = 0x01000007
+
+	testTextSize     = 0x4000
+	testLinkeditSize = 0x40
+	testTeamID       = "TEAMID1234"
+	testP12Password  = "secret"
+)
+
+// testTimestampTime is the time of the timestamps of the test server. It is
+// within the validity of the test certificate, and before the verification.
+var testTimestampTime = time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+// testMachO returns a minimal executable with the __TEXT and __LINKEDIT
+// segments and room for LC_CODE_SIGNATURE.
+func testMachO(cpuType uint32) []byte {
+	data := make([]byte, testTextSize+testLinkeditSize)
+	le := binary.LittleEndian
+	le.PutUint32(data[0:], machOMagic64)
+	le.PutUint32(data[4:], cpuType)
+	le.PutUint32(data[12:], machOFileTypeExecute)
+	le.PutUint32(data[16:], 2)
+	le.PutUint32(data[20:], 2*segment64HeaderSize)
+
+	segment := func(offset int, name string, fileOffset, fileSize uint64) {
+		le.PutUint32(data[offset:], loadCommandSegment64)
+		le.PutUint32(data[offset+4:], segment64HeaderSize)
+		copy(data[offset+segment64NameOffset:], name)
+		le.PutUint64(data[offset+24:], fileOffset)
+		le.PutUint64(data[offset+segment64VMSizeOffset:], alignUp64(fileSize, 0x4000))
+		le.PutUint64(data[offset+segment64FileOffOffset:], fileOffset)
+		le.PutUint64(data[offset+segment64FileSizeOffset:], fileSize)
+	}
+	segment(machOHeaderSize64, "__TEXT", 0, testTextSize)
+	segment(machOHeaderSize64+segment64HeaderSize, "__LINKEDIT", testTextSize, testLinkeditSize)
+
+	for i := 0x1000; i < len(data); i++ {
+		data[i] = byte(i * 7)
+	}
+	return data
+}
+
+// testUniversal returns a universal binary of the slices.
+func testUniversal(slices ...[]byte) []byte {
+	file := &machOFile{fat: true}
+	for _, data := range slices {
+		slice, err := parseMachOSlice(data)
+		if err != nil {
+			panic(err)
+		}
+		file.slices = append(file.slices, slice)
+	}
+	return file.bytes()
+}
+
+// newTestCertificate creates a self-signed Developer ID Application certificate.
+func newTestCertificate(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:         "Developer ID Application: Test (" + testTeamID + ")",
+			OrganizationalUnit: []string{testTeamID},
+		},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning, x509.ExtKeyUsageTimeStamping},
+		ExtraExtensions: []pkix.Extension{{Id: oidDeveloperIDApplication, Value: asn1.NullRawValue.FullBytes}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate, key
+}
+
+// newTestTimestampServer starts an RFC 3161 server that grants every request
+// with a token signed by the certificate.
+func newTestTimestampServer(t *testing.T, certificate *x509.Certificate, key crypto.Signer) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Version        int
+			MessageImprint asn1.RawValue
+			Nonce          *big.Int `asn1:"optional"`
+			CertReq        bool     `asn1:"optional"`
+		}
+		if _, err := asn1.Unmarshal(body, &request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		token, err := testTimestampToken(request.MessageImprint, request.Nonce, certificate, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response, err := asn1.Marshal(struct {
+			Status         struct{ Status int }
+			TimeStampToken asn1.RawValue
+		}{TimeStampToken: asn1.RawValue{FullBytes: token}})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/timestamp-reply")
+		w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testTimestampToken creates a SignedData with the TSTInfo as its content.
+func testTimestampToken(imprint asn1.RawValue, nonce *big.Int, certificate *x509.Certificate, key crypto.Signer) ([]byte, error) {
+	tstInfo, err := asn1.Marshal(struct {
+		Version        int
+		Policy         asn1.ObjectIdentifier
+		MessageImprint asn1.RawValue
+		SerialNumber   *big.Int
+		GenTime        time.Time `asn1:"generalized"`
+		Nonce          *big.Int  `asn1:"optional"`
+	}{1, asn1.ObjectIdentifier{1, 2, 3}, imprint, big.NewInt(1), testTimestampTime, nonce})
+	if err != nil {
+		return nil, err
+	}
+	detached, err := base.CMSSigner{Certificate: certificate, Key: key}.SignDetached(tstInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Embed the TSTInfo into the detached signature.
+	var info struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+	if _, err := asn1.Unmarshal(detached, &info); err != nil {
+		return nil, err
+	}
+	var signedData struct {
+		Version          int
+		DigestAlgorithms asn1.RawValue
+		EncapContentInfo struct {
+			ContentType asn1.ObjectIdentifier
+			Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+		}
+		Certificates asn1.RawValue `asn1:"optional,tag:0"`
+		SignerInfos  asn1.RawValue
+	}
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &signedData); err != nil {
+		return nil, err
+	}
+	signedData.Version = 3
+	signedData.EncapContentInfo.ContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	content, err := asn1.Marshal(tstInfo)
+	if err != nil {
+		return nil, err
+	}
+	signedData.EncapContentInfo.Content = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content}
+	encoded, err := asn1.Marshal(signedData)
+	if err != nil {
+		return nil, err
+	}
+	info.Content = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: encoded}
+	return asn1.Marshal(info)
+}
+
+// writeTestP12 writes the certificate and the key to a PKCS #12 file.
+func writeTestP12(t *testing.T, certificate *x509.Certificate, key crypto.Signer) string {
+	t.Helper()
+	data, err := pkcs12.Modern2023.Encode(key, certificate, nil, testP12Password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "developer-id.p12")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tamper flips a byte of the file at the offset.
+func tamper(t *testing.T, path string, offset int) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[offset] ^= 0xFF
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// embeddedSignatureOf returns the code signature of the first slice of the Mach-O file.
+func embeddedSignatureOf(t *testing.T, path string) *embeddedSignature {
+	t.Helper()
+	file, err := readMachOFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := parseEmbeddedSignature(file.slices[0].signature())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signature
+}
+
+// designatedRequirementOf decompiles the designated requirement of the requirement set.
+func designatedRequirementOf(t *testing.T, requirements []byte) string {
+	t.Helper()
+	be := binary.BigEndian
+	if len(requirements) < 12 || be.Uint32(requirements) != blobMagicRequirements {
+		t.Fatal("invalid requirement set")
+	}
+	for i := 0; i < int(be.Uint32(requirements[8:])); i++ {
+		if be.Uint32(requirements[12+i*8:]) != requirementTypeDesignated {
+			continue
+		}
+		requirement := requirements[be.Uint32(requirements[16+i*8:]):]
+		if be.Uint32(requirement) != blobMagicRequirement || be.Uint32(requirement[8:]) != requirementExprForm {
+			t.Fatal("invalid designated requirement")
+		}
+		code := requirement[12:be.Uint32(requirement[4:])]
+		text := decompileRequirement(t, &code)
+		if len(code) != 0 {
+			t.Fatalf("%d bytes left after the requirement %s", len(code), text)
+		}
+		return text
+	}
+	t.Fatal("the requirement set has no designated requirement")
+	return ""
+}
+
+// decompileRequirement decompiles the requirement expression at the start of the code.
+func decompileRequirement(t *testing.T, code *[]byte) string {
+	t.Helper()
+	next := func() uint32 {
+		if len(*code) < 4 {
+			t.Fatal("the requirement is truncated")
+		}
+		value := binary.BigEndian.Uint32(*code)
+		*code = (*code)[4:]
+		return value
+	}
+	data := func() []byte {
+		length := int(next())
+		padded := (length + 3) &^ 3
+		if len(*code) < padded {
+			t.Fatal("the requirement data is truncated")
+		}
+		value := (*code)[:length]
+		*code = (*code)[padded:]
+		return value
+	}
+	certificate := func() string {
+		if slot := next(); slot != certificateSlotLeaf {
+			return fmt.Sprintf("certificate %d", slot)
+		}
+		return "certificate leaf"
+	}
+
+	switch op := next(); op {
+	case requirementOpAnd:
+		left := decompileRequirement(t, code)
+		return left + " and " + decompileRequirement(t, code)
+	case requirementOpIdent:
+		return fmt.Sprintf(`identifier "%s"`, data())
+	case requirementOpAppleGenericAnchor:
+		return "anchor apple generic"
+	case requirementOpAnchorHash:
+		name := certificate()
+		return fmt.Sprintf(`%s = H"%x"`, name, data())
+	case requirementOpCertGeneric:
+		name := certificate()
+		var oid asn1.ObjectIdentifier
+		content := data()
+		if _, err := asn1.Unmarshal(append([]byte{asn1.TagOID, byte(len(content))}, content...), &oid); err != nil {
+			t.Fatal(err)
+		}
+		if match := next(); match != requirementMatchExists {
+			t.Fatalf("unexpected match operation %d", match)
+		}
+		return fmt.Sprintf("%s[field.%s] /* exists */", name, oid)
+	case requirementOpCertField:
+		name := certificate()
+		field := data()
+		if match := next(); match != requirementMatchEqual {
+			t.Fatalf("unexpected match operation %d", match)
+		}
+		return fmt.Sprintf(`%s[%s] = "%s"`, name, field, data())
+	default:
+		t.Fatalf("unexpected requirement opcode %d", op)
+		return ""
+	}
+}
+
+// cmsSignatureOf returns the CMS signature of the first slice of the Mach-O file.
+func cmsSignatureOf(t *testing.T, path string) *base.SignedData {
+	t.Helper()
+	cms := embeddedSignatureOf(t, path).blobs[slotSignature]
+	if len(cms) <= 8 {
+		return nil
+	}
+	signedData, err := base.ParseSignedData(cms[8:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedData
+}
+
+func TestGoCodeSignerAdhocRoundTrip(t *testing.T) {
+	signer, err := newGoCodeSigner(common.BrandingParams{Mac: common.Mac{CodesignIdentity: adhocIdentity}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	binaries := map[string][]byte{
+		"arm64":     testMachO(cpuTypeARM64),
+		"universal": testUniversal(testMachO(cpuTypeARM64), testMachO(cpuTypeX86_64)),
+	}
+	for name, data := range binaries {
+		path := filepath.Join(t.TempDir(), name)
+		writeTestFile(t, path, data)
+		if err := signer.sign(path, ""); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := signer.verify(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cmsSignatureOf(t, path) != nil {
+			t.Fatalf("%s: the ad-hoc signature has a CMS signature", name)
+		}
+
+		// Signing again replaces the signature.
+		if err := signer.sign(path, ""); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := signer.verify(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		tamper(t, path, len(data)-testLinkeditSize-1)
+		if err := signer.verify(path); err == nil {
+			t.Fatalf("%s: the modified code passes verification", name)
+		}
+	}
+}
+
+func TestGoCodeSignerPKCS12RoundTrip(t *testing.T) {
+	certificate, key := newTestCertificate(t)
+	timestampServer := newTestTimestampServer(t, certificate, key)
+	signer, err := newGoCodeSigner(common.BrandingParams{Mac: common.Mac{
+		CodesignCertificate:         writeTestP12(t, certificate, key),
+		CodesignCertificatePassword: testP12Password,
+		TimestampUrl:                timestampServer.URL,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.teamID != testTeamID {
+		t.Fatalf("the Team ID is %q, expected %s", signer.teamID, testTeamID)
+	}
+
+	app := writeTestBundle(t, t.TempDir())
+	entitlements := filepath.Join(t.TempDir(), "entitlements.plist")
+	writeTestFile(t, entitlements, []byte(testEntitlements))
+	nested := filepath.Join(app, "Contents", "Frameworks", "libtest.dylib")
+	for _, path := range []string{nested, app} {
+		if err := signer.sign(path, entitlements); err != nil {
+			t.Fatal(err)
+		}
+		if err := signer.verify(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	executable := filepath.Join(app, "Contents", "MacOS", "Test")
+	codeDirectory := embeddedSignatureOf(t, executable).blobs[slotCodeDirectory]
+	teamOffset := binary.BigEndian.Uint32(codeDirectory[48:])
+	if teamOffset == 0 || int(teamOffset) >= len(codeDirectory) {
+		t.Fatalf("the code directory has the Team ID offset %d", teamOffset)
+	}
+	if teamID, _, _ := bytes.Cut(codeDirectory[teamOffset:], []byte{0}); string(teamID) != testTeamID {
+		t.Errorf("the code directory has the Team ID %q, expected %q", teamID, testTeamID)
+	}
+
+	signature := embeddedSignatureOf(t, executable)
+	wantRequirement := `identifier "com.example.test" and anchor apple generic and certificate 1[field.1.2.840.113635.100.6.2.6] /* exists */ and certificate leaf[field.1.2.840.113635.100.6.1.13] /* exists */ and certificate leaf[subject.OU] = "` + testTeamID + `"`
+	if requirement := designatedRequirementOf(t, signature.blobs[slotRequirements]); requirement != wantRequirement {
+		t.Errorf("the designated requirement is\n%s\nexpected\n%s", requirement, wantRequirement)
+	}
+	if specialCount := binary.BigEndian.Uint32(codeDirectory[24:]); specialCount != slotDEREntitlements {
+		t.Errorf("the code directory has %d special slots, expected %d", specialCount, slotDEREntitlements)
+	}
+	// The version 1 and the dictionary with the single key.
+	wantDER := "702b020101b0263024" + "0c1f" + hex.EncodeToString([]byte("com.apple.security.cs.allow-jit")) + "0101ff"
+	if der := signature.blobs[slotDEREntitlements]; len(der) < 8 || binary.BigEndian.Uint32(der) != blobMagicDEREntitlements {
+		t.Error("the code signature has no DER entitlements")
+	} else if hex.EncodeToString(der[8:]) != wantDER {
+		t.Errorf("the DER entitlements are %x, expected %s", der[8:], wantDER)
+	}
+
+	signedData := cmsSignatureOf(t, executable)
+	if signedData == nil {
+		t.Fatal("the signature has no CMS signature")
+	}
+	if !signedData.Signer.Equal(certificate) {
+		t.Error("the CMS signature is not made with the certificate")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(certificate)
+	if signingTime := signedData.SigningTime(roots); !signingTime.Equal(testTimestampTime) {
+		t.Errorf("the signing time is %v, expected the timestamp time %v", signingTime, testTimestampTime)
+	}
+	if signingTime := signedData.SigningTime(x509.NewCertPool()); signingTime.Equal(testTimestampTime) {
+		t.Error("the time of an untrusted timestamp is used as the signing time")
+	}
+
+	resources, err := os.ReadFile(filepath.Join(app, "Contents", "_CodeSignature", "CodeResources"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirement := `certificate leaf[subject.OU] = "` + testTeamID + `"`
+	if !bytes.Contains(resources, []byte(requirement)) {
+		t.Errorf("CodeResources has no designated requirement of the nested code with %s", requirement)
+	}
+
+	tamper(t, filepath.Join(app, "Contents", "Resources", "en.lproj", "Localizable.strings"), 0)
+	if err := signer.verify(app); err == nil {
+		t.Fatal("the modified resource passes verification")
+	}
+}
+
+func TestGoCodeSignerRejectsWrongPassword(t *testing.T) {
+	certificate, key := newTestCertificate(t)
+	_, err := newGoCodeSigner(common.BrandingParams{Mac: common.Mac{
+		CodesignCertificate:         writeTestP12(t, certificate, key),
+		CodesignCertificatePassword: "wrong",
+	}})
+	if err == nil {
+		t.Fatal("the PKCS #12 file is decoded with a wrong password")
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/png"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	icnsMagic      = "icns"
+	icnsHeaderSize = 8
+)
+
+// icnsIconType describes an image slot of the ICNS container.
+type icnsIconType struct {
+	// osType is the four-character code identifying the image in the container.
+	osType string
+	// size is the icon size in points.
+	size int
+	// scale is the number of pixels per point (1 or 2 for @2x).
+	scale int
+}
+
+// pixels returns the expected width and height of the image in pixels.
+func (iconType icnsIconType) pixels() int {
+	return iconType.size * iconType.scale
+}
+
+// fileName returns the name of the iconset file holding the image,
+// as produced by Xcode and expected by iconutil.
+func (iconType icnsIconType) fileName() string {
+	name := fmt.Sprintf("icon_%dx%d", iconType.size, iconType.size)
+	if iconType.scale == 2 {
+		name += "@2x"
+	}
+	return name + ".png"
+}
+
+// icnsIconTypes lists the PNG-based image slots of the ICNS container
+// in the order they are written into the file.
+var icnsIconTypes = []icnsIconType{
+	{osType: "icp4", size: 16, scale: 1},
+	{osType: "ic11", size: 16, scale: 2},
+	{osType: "icp5", size: 32, scale: 1},
+	{osType: "ic12", size: 32, scale: 2},
+	{osType: "ic07", size: 128, scale: 1},
+	{osType: "ic13", size: 128, scale: 2},
+	{osType: "ic08", size: 256, scale: 1},
+	{osType: "ic14", size: 256, scale: 2},
+	{osType: "ic09", size: 512, scale: 1},
+	{osType: "ic10", size: 512, scale: 2},
+}
+
+// iconsetFileNamePattern matches the iconset file names such as "icon_16x16.png"
+// and "icon_16x16@2x.png". The repeated "@2x@2x" suffix is accepted as well.
+var iconsetFileNamePattern = regexp.MustCompile(`^icon_(\d+)x(\d+)((?:@2x)*)\.png$`)
+
+// icnsEntry is a single image stored in the ICNS container.
+type icnsEntry struct {
+	osType string
+	data   []byte
+}
+
+// buildICNSFromIconset reads the PNG files from the iconsetDir directory
+// and encodes them into the ICNS container.
+//
+// The files must be named as in an Xcode .iconset directory (e.g., "icon_16x16.png",
+// "icon_16x16@2x.png"). Returns an error listing all the missing sizes,
+// unrecognized files, and images with unexpected pixel dimensions.
+func buildICNSFromIconset(iconsetDir base.Directory) ([]byte, error) {
+	images := map[string][]byte{}
+	problems := []error{}
+
+	for _, file := range iconsetDir.ListFiles() {
+		fileName := file.AbsPath().Base()
+		if !strings.EqualFold(filepath.Ext(fileName), ".png") {
+			continue
+		}
+
+		iconType, err := iconTypeFromFileName(fileName)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+
+		data, err := file.Read()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIconsetImage(fileName, data, iconType.pixels()); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if _, exists := images[iconType.osType]; exists {
+			problems = append(problems, fmt.Errorf("%s: duplicates the %s image", fileName, iconType.fileName()))
+			continue
+		}
+		images[iconType.osType] = data
+	}
+
+	entries := []icnsEntry{}
+	for _, iconType := range icnsIconTypes {
+		data, ok := images[iconType.osType]
+		if !ok {
+			problems = append(problems, fmt.Errorf("missing %s (%dx%d pixels)", iconType.fileName(), iconType.pixels(), iconType.pixels()))
+			continue
+		}
+		entries = append(entries, icnsEntry{osType: iconType.osType, data: data})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid iconset %s:\n%w", iconsetDir.AbsPath().String(), errors.Join(problems...))
+	}
+	return encodeICNS(entries), nil
+}
+
+// buildICNSFromIcon generates all the images of the ICNS container from
+// the master icon.
+func buildICNSFromIcon(icon *common.Icon) ([]byte, error) {
+	entries := []icnsEntry{}
+	for _, iconType := range icnsIconTypes {
+		data, err := icon.PNG(iconType.pixels())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, icnsEntry{osType: iconType.osType, data: data})
+	}
+	return encodeICNS(entries), nil
+}
+
+// writeICNSFromIconset builds the ICNS container from the iconset located at
+// iconsetPath and writes it into a temporary file. Returns the path to the file;
+// the caller is responsible for removing it.
+func writeICNSFromIconset(iconsetPath string) (base.AbsPath, error) {
+	iconsetDir, err := base.DirectoryFromPathString(iconsetPath)
+	if err != nil {
+		return base.AbsPath{}, err
+	}
+	icns, err := buildICNSFromIconset(iconsetDir)
+	if err != nil {
+		return base.AbsPath{}, err
+	}
+	return writeTempICNS(icns)
+}
+
+// writeICNSFromIcon builds the ICNS container from the master icon and writes
+// it into a temporary file. Returns the path to the file; the caller is
+// responsible for removing it.
+func writeICNSFromIcon(icon *common.Icon) (base.AbsPath, error) {
+	icns, err := buildICNSFromIcon(icon)
+	if err != nil {
+		return base.AbsPath{}, err
+	}
+	return writeTempICNS(icns)
+}
+
+func writeTempICNS(icns []byte) (base.AbsPath, error) {
+	tmpPath, err := base.WriteTempFile("app-*.icns", icns)
+	if err != nil {
+		return base.AbsPath{}, err
+	}
+	return base.AbsPathFromPathString(tmpPath)
+}
+
+// iconTypeFromFileName returns the ICNS image slot for the given iconset file name.
+func iconTypeFromFileName(fileName string) (icnsIconType, error) {
+	match := iconsetFileNamePattern.FindStringSubmatch(strings.ToLower(fileName))
+	if match == nil {
+		return icnsIconType{}, fmt.Errorf("%s: unrecognized file name, expected icon_<size>x<size>[@2x].png", fileName)
+	}
+	width, _ := strconv.Atoi(match[1])
+	height, _ := strconv.Atoi(match[2])
+	scale := 1
+	if match[3] != "" {
+		scale = 2
+	}
+	for _, iconType := range icnsIconTypes {
+		if iconType.size == width && iconType.size == height && iconType.scale == scale {
+			return iconType, nil
+		}
+	}
+	return icnsIconType{}, fmt.Errorf("%s: unsupported icon size", fileName)
+}
+
+// checkIconsetImage checks that data is a PNG image of pixels x pixels size.
+func checkIconsetImage(fileName string, data []byte, pixels int) error {
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: not a valid PNG image: %w", fileName, err)
+	}
+	if config.Width != pixels || config.Height != pixels {
+		return fmt.Errorf("%s: expected %dx%d pixels, got %dx%d", fileName, pixels, pixels, config.Width, config.Height)
+	}
+	return nil
+}
+
+// encodeICNS writes the given images into the ICNS container.
+// All the multi-byte values in the container are big-endian.
+func encodeICNS(entries []icnsEntry) []byte {
+	totalSize := icnsHeaderSize
+	for _, entry := range entries {
+		totalSize += icnsHeaderSize + len(entry.data)
+	}
+
+	out := make([]byte, 0, totalSize)
+	out = append(out, icnsMagic...)
+	out = binary.BigEndian.AppendUint32(out, uint32(totalSize))
+	for _, entry := range entries {
+		out = append(out, entry.osType...)
+		out = binary.BigEndian.AppendUint32(out, uint32(icnsHeaderSize+len(entry.data)))
+		out = append(out, entry.data...)
+	}
+	return out
+}
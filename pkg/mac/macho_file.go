@@ -0,0 +1,309 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	machOHeaderSize64 = 32
+	fatHeaderSize     = 8
+	fatArchSize       = 20
+
+	loadCommandSegment64     = 0x19
+	loadCommandCodeSignature = 0x1D
+	linkeditDataCommandSize  = 16
+
+	machOFileTypeExecute = 2
+
+	cpuTypeARM64 = 0x0100000C
+
+	segment64NameOffset     = 8
+	segment64VMSizeOffset   = 32
+	segment64FileOffOffset  = 40
+	segment64FileSizeOffset = 48
+	segment64NSectsOffset   = 64
+	segment64HeaderSize     = 72
+	section64Size           = 80
+	section64OffsetOffset   = 48
+)
+
+// machOSlice is a 64-bit little-endian Mach-O image: a thin file, or one
+// architecture of a universal binary.
+type machOSlice struct {
+	data []byte
+
+	cpuType  uint32
+	cpuSub   uint32
+	fileType uint32
+	align    uint32
+
+	// The offsets of the load commands in data, or -1 if missing.
+	linkedit      int
+	text          int
+	codeSignature int
+}
+
+// machOFile is a thin or universal Mach-O file.
+type machOFile struct {
+	slices []*machOSlice
+	fat    bool
+}
+
+// readMachOFile reads and parses the Mach-O file at path.
+func readMachOFile(path string) (*machOFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parseMachOFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+func parseMachOFile(data []byte) (*machOFile, error) {
+	if len(data) < 8 {
+		return nil, errors.New("not a Mach-O file")
+	}
+	if binary.BigEndian.Uint32(data) != fatMagic {
+		slice, err := parseMachOSlice(data)
+		if err != nil {
+			return nil, err
+		}
+		return &machOFile{slices: []*machOSlice{slice}}, nil
+	}
+
+	count := int(binary.BigEndian.Uint32(data[4:]))
+	if count > maxFatArchitectures || fatHeaderSize+count*fatArchSize > len(data) {
+		return nil, errors.New("invalid universal binary header")
+	}
+	file := &machOFile{fat: true}
+	for i := 0; i < count; i++ {
+		arch := data[fatHeaderSize+i*fatArchSize:]
+		offset := int(binary.BigEndian.Uint32(arch[8:]))
+		size := int(binary.BigEndian.Uint32(arch[12:]))
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("architecture %d exceeds the file size", i)
+		}
+		slice, err := parseMachOSlice(append([]byte(nil), data[offset:offset+size]...))
+		if err != nil {
+			return nil, err
+		}
+		slice.align = binary.BigEndian.Uint32(arch[16:])
+		file.slices = append(file.slices, slice)
+	}
+	return file, nil
+}
+
+func parseMachOSlice(data []byte) (*machOSlice, error) {
+	if len(data) < machOHeaderSize64 {
+		return nil, errors.New("not a Mach-O file")
+	}
+	switch binary.LittleEndian.Uint32(data) {
+	case machOMagic64:
+	case machOMagic32:
+		return nil, errors.New("32-bit Mach-O files are not supported")
+	default:
+		return nil, errors.New("not a little-endian Mach-O file")
+	}
+	slice := &machOSlice{
+		data:          data,
+		cpuType:       binary.LittleEndian.Uint32(data[4:]),
+		cpuSub:        binary.LittleEndian.Uint32(data[8:]),
+		fileType:      binary.LittleEndian.Uint32(data[12:]),
+		align:         14,
+		linkedit:      -1,
+		text:          -1,
+		codeSignature: -1,
+	}
+	count := int(binary.LittleEndian.Uint32(data[16:]))
+	offset := machOHeaderSize64
+	for i := 0; i < count; i++ {
+		if offset+8 > len(data) {
+			return nil, errors.New("the load commands are truncated")
+		}
+		command := binary.LittleEndian.Uint32(data[offset:])
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if size < 8 || offset+size > len(data) {
+			return nil, errors.New("invalid load command size")
+		}
+		switch command {
+		case loadCommandSegment64:
+			name := string(bytes.TrimRight(data[offset+segment64NameOffset:offset+segment64NameOffset+16], "\x00"))
+			switch name {
+			case "__LINKEDIT":
+				slice.linkedit = offset
+			case "__TEXT":
+				slice.text = offset
+			}
+		case loadCommandCodeSignature:
+			slice.codeSignature = offset
+		}
+		offset += size
+	}
+	if slice.linkedit < 0 || slice.text < 0 {
+		return nil, errors.New("the __TEXT or __LINKEDIT segment is missing")
+	}
+	return slice, nil
+}
+
+func (slice *machOSlice) uint32At(offset int) uint32 {
+	return binary.LittleEndian.Uint32(slice.data[offset:])
+}
+
+func (slice *machOSlice) uint64At(offset int) uint64 {
+	return binary.LittleEndian.Uint64(slice.data[offset:])
+}
+
+// pageSize returns the virtual memory page size of the architecture.
+func (slice *machOSlice) pageSize() uint64 {
+	if slice.cpuType == cpuTypeARM64 {
+		return 0x4000
+	}
+	return 0x1000
+}
+
+// signature returns the code signature of the slice, or nil if not signed.
+func (slice *machOSlice) signature() []byte {
+	if slice.codeSignature < 0 {
+		return nil
+	}
+	offset := int(slice.uint32At(slice.codeSignature + 8))
+	size := int(slice.uint32At(slice.codeSignature + 12))
+	if offset+size > len(slice.data) {
+		return nil
+	}
+	return slice.data[offset : offset+size]
+}
+
+// codeLimit returns the offset of the code signature, which is also the limit
+// of the signed code, adding the LC_CODE_SIGNATURE command if missing.
+func (slice *machOSlice) codeLimit() (int, error) {
+	if slice.codeSignature >= 0 {
+		offset := int(slice.uint32At(slice.codeSignature + 8))
+		if offset > len(slice.data) {
+			return 0, errors.New("the code signature offset exceeds the file size")
+		}
+		return offset, nil
+	}
+	linkeditEnd := slice.uint64At(slice.linkedit+segment64FileOffOffset) + slice.uint64At(slice.linkedit+segment64FileSizeOffset)
+	if int(linkeditEnd) != len(slice.data) {
+		return 0, errors.New("the __LINKEDIT segment does not end the file")
+	}
+	if err := slice.addCodeSignatureCommand(); err != nil {
+		return 0, err
+	}
+	return int(alignUp64(linkeditEnd, 16)), nil
+}
+
+// reserveSignature replaces everything from the offset with the room for
+// a signature of the given size at the end of the __LINKEDIT segment.
+func (slice *machOSlice) reserveSignature(offset, size int) {
+	data := make([]byte, offset+size)
+	copy(data, slice.data[:offset])
+	slice.data = data
+
+	binary.LittleEndian.PutUint32(slice.data[slice.codeSignature+8:], uint32(offset))
+	binary.LittleEndian.PutUint32(slice.data[slice.codeSignature+12:], uint32(size))
+	linkeditSize := uint64(len(slice.data)) - slice.uint64At(slice.linkedit+segment64FileOffOffset)
+	binary.LittleEndian.PutUint64(slice.data[slice.linkedit+segment64FileSizeOffset:], linkeditSize)
+	if vmSize := alignUp64(linkeditSize, slice.pageSize()); vmSize > slice.uint64At(slice.linkedit+segment64VMSizeOffset) {
+		binary.LittleEndian.PutUint64(slice.data[slice.linkedit+segment64VMSizeOffset:], vmSize)
+	}
+}
+
+// addCodeSignatureCommand appends LC_CODE_SIGNATURE to the load commands if
+// there is room before the first section.
+func (slice *machOSlice) addCodeSignatureCommand() error {
+	count := slice.uint32At(16)
+	commandsSize := slice.uint32At(20)
+	end := machOHeaderSize64 + int(commandsSize)
+
+	firstSection := len(slice.data)
+	offset := machOHeaderSize64
+	for i := 0; i < int(count); i++ {
+		if slice.uint32At(offset) == loadCommandSegment64 {
+			sections := int(slice.uint32At(offset + segment64NSectsOffset))
+			for j := 0; j < sections; j++ {
+				sectionOffset := int(slice.uint32At(offset + segment64HeaderSize + j*section64Size + section64OffsetOffset))
+				if sectionOffset != 0 && sectionOffset < firstSection {
+					firstSection = sectionOffset
+				}
+			}
+		}
+		offset += int(slice.uint32At(offset + 4))
+	}
+	if end+linkeditDataCommandSize > firstSection {
+		return errors.New("no room for the code signature load command")
+	}
+	for _, b := range slice.data[end : end+linkeditDataCommandSize] {
+		if b != 0 {
+			return errors.New("no room for the code signature load command")
+		}
+	}
+
+	binary.LittleEndian.PutUint32(slice.data[end:], loadCommandCodeSignature)
+	binary.LittleEndian.PutUint32(slice.data[end+4:], linkeditDataCommandSize)
+	binary.LittleEndian.PutUint32(slice.data[16:], count+1)
+	binary.LittleEndian.PutUint32(slice.data[20:], commandsSize+linkeditDataCommandSize)
+	slice.codeSignature = end
+	return nil
+}
+
+// bytes returns the content of the file with all its slices.
+func (file *machOFile) bytes() []byte {
+	if !file.fat {
+		return file.slices[0].data
+	}
+	headerSize := uint64(fatHeaderSize + len(file.slices)*fatArchSize)
+	offsets := []uint64{}
+	end := headerSize
+	for _, slice := range file.slices {
+		offset := alignUp64(end, 1<<slice.align)
+		offsets = append(offsets, offset)
+		end = offset + uint64(len(slice.data))
+	}
+
+	out := make([]byte, end)
+	binary.BigEndian.PutUint32(out, fatMagic)
+	binary.BigEndian.PutUint32(out[4:], uint32(len(file.slices)))
+	for i, slice := range file.slices {
+		arch := out[fatHeaderSize+i*fatArchSize:]
+		binary.BigEndian.PutUint32(arch[0:], slice.cpuType)
+		binary.BigEndian.PutUint32(arch[4:], slice.cpuSub)
+		binary.BigEndian.PutUint32(arch[8:], uint32(offsets[i]))
+		binary.BigEndian.PutUint32(arch[12:], uint32(len(slice.data)))
+		binary.BigEndian.PutUint32(arch[16:], slice.align)
+		copy(out[offsets[i]:], slice.data)
+	}
+	return out
+}
+
+func alignUp64(value, alignment uint64) uint64 {
+	return (value + alignment - 1) / alignment * alignment
+}
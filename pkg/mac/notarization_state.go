@@ -0,0 +1,150 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// notarizationStateFile is the file in the output directory that keeps
+// the submission between `notarize submit` and `notarize finalize`.
+const notarizationStateFile = ".notarization.json"
+
+// notarizationState is the submitted app bundle and its submission.
+type notarizationState struct {
+	SubmissionId string
+	AppBundle    string
+	Submitted    time.Time
+}
+
+// SubmitNotarization submits the app bundle in the output directory for
+// notarization without waiting for the result, and saves the submission ID
+// to the state file in the output directory.
+func SubmitNotarization(outDir string, params common.BrandingParams) (string, error) {
+	backend, outDirPath, appBundleName, err := prepareNotarization(outDir, params)
+	if err != nil {
+		return "", err
+	}
+	id, err := submitApp(backend, outDirPath, appBundleName)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(notarizationState{
+		SubmissionId: id,
+		AppBundle:    appBundleName,
+		Submitted:    time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(outDirPath, notarizationStateFile), data, 0644); err != nil {
+		return "", fmt.Errorf("saving the submission %s: %w", id, err)
+	}
+	return id, nil
+}
+
+// NotarizationStatus returns the submission ID saved by SubmitNotarization
+// and the current status of the submission.
+func NotarizationStatus(outDir string, params common.BrandingParams) (string, string, error) {
+	backend, outDirPath, appBundleName, err := prepareNotarization(outDir, params)
+	if err != nil {
+		return "", "", err
+	}
+	state, err := readNotarizationState(outDirPath, appBundleName)
+	if err != nil {
+		return "", "", err
+	}
+	status, err := backend.status(state.SubmissionId)
+	if err != nil {
+		return "", "", err
+	}
+	return state.SubmissionId, status, nil
+}
+
+// FinalizeNotarization waits for the result of the submission saved by
+// SubmitNotarization, verifies the app bundle, and staples the ticket to it.
+// The state file is removed once the ticket is stapled.
+func FinalizeNotarization(outDir string, params common.BrandingParams) error {
+	backend, outDirPath, appBundleName, err := prepareNotarization(outDir, params)
+	if err != nil {
+		return err
+	}
+	state, err := readNotarizationState(outDirPath, appBundleName)
+	if err != nil {
+		return err
+	}
+	base.Log("Finalizing the submission " + state.SubmissionId + " of " + appBundleName + "...")
+	if err := finishNotarization(backend, state.SubmissionId, outDirPath, appBundleName); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(outDirPath, notarizationStateFile))
+}
+
+// prepareNotarization creates the notary backend and returns it with
+// the absolute output directory and the name of the app bundle.
+func prepareNotarization(outDir string, params common.BrandingParams) (notaryBackend, string, string, error) {
+	if params.Mac.Bundle == nil || params.Mac.Bundle.Name == nil {
+		return nil, "", "", errors.New("mac.bundle.name is not set")
+	}
+	credentials, err := notaryCredentials(params)
+	if err != nil {
+		return nil, "", "", err
+	}
+	backend, err := newNotaryBackend(params, credentials)
+	if err != nil {
+		return nil, "", "", err
+	}
+	outDirPath, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return backend, outDirPath, *params.Mac.Bundle.Name + ".app", nil
+}
+
+func readNotarizationState(outDirPath string, appBundleName string) (*notarizationState, error) {
+	path := filepath.Join(outDirPath, notarizationStateFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s not found, run `notarize submit` first", path)
+	} else if err != nil {
+		return nil, err
+	}
+	state := &notarizationState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if state.SubmissionId == "" {
+		return nil, fmt.Errorf("%s has no submission ID", path)
+	}
+	if state.AppBundle != appBundleName {
+		return nil, fmt.Errorf("%s was submitted rather than %s", state.AppBundle, appBundleName)
+	}
+	return state, nil
+}
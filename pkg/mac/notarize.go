@@ -21,10 +21,13 @@
 package mac
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
@@ -48,23 +51,203 @@ func archiveApp(bundleName string, outDir string) (string, error) {
 	return bundleZip, nil
 }
 
-func notarize(appBundlePath string, teamID string, appleID string, password string) error {
-	base.Log("Notarizing " + appBundlePath + " (it may take a while)...")
-	commandArgs := []string{
-		"notarytool",
-		"submit", appBundlePath,
-		"--team-id", base.GetValue(teamID),
-		"--apple-id", base.GetValue(appleID),
-		"--password", base.GetValue(password),
-		"--output-format", "plist",
-		"--wait"}
+const (
+	notaryToolAPI        = "api"
+	notaryToolNotarytool = "notarytool"
+
+	notaryStatusInProgress = "In Progress"
+	notaryStatusAccepted   = "Accepted"
+)
+
+var (
+	// notaryPollDelay is the delay before the first check of the status
+	// of a submission, doubled after every check up to notaryMaxPollDelay.
+	notaryPollDelay    = 15 * time.Second
+	notaryMaxPollDelay = 2 * time.Minute
+
+	// notaryTimeout is how long a submission may stay in progress.
+	notaryTimeout = 3 * time.Hour
+)
+
+// notaryBackend submits the archives to the notary service and tracks
+// the submissions.
+type notaryBackend interface {
+	submit(archivePath string) (string, error)
+	status(id string) (string, error)
+	log(id string) ([]byte, error)
+}
+
+// newNotaryBackend returns the Notary API client if `mac.notarization.tool`
+// is "api", or if it is not set and an API key is given, and notarytool
+// otherwise.
+func newNotaryBackend(params common.BrandingParams, credentials []string) (notaryBackend, error) {
+	notarization := params.Mac.Notarization
+	tool := ""
+	if notarization != nil {
+		tool = notarization.Tool
+	}
+	switch tool {
+	case notaryToolNotarytool:
+		return notarytoolBackend{credentials}, nil
+	case notaryToolAPI, "":
+		if notarization == nil || notarization.ApiKey == "" {
+			if tool == notaryToolAPI {
+				return nil, errors.New("the Notary API needs mac.notarization.apiKey")
+			}
+			return notarytoolBackend{credentials}, nil
+		}
+		return newNotaryClient(
+			notarization.ServiceUrl,
+			notarization.UploadUrl,
+			base.GetValue(notarization.ApiKey),
+			base.GetValue(notarization.ApiKeyId),
+			base.GetValue(notarization.ApiIssuer))
+	default:
+		return nil, fmt.Errorf("unknown mac.notarization.tool %q, expected %s or %s", tool, notaryToolAPI, notaryToolNotarytool)
+	}
+}
+
+// notarytoolBackend notarizes with `xcrun notarytool`.
+type notarytoolBackend struct {
+	credentials []string
+}
+
+func (backend notarytoolBackend) submit(archivePath string) (string, error) {
+	output, err := backend.run("submit", archivePath)
+	if err != nil {
+		return "", err
+	}
+	id, _ := output.get("id").(string)
+	if id == "" {
+		return "", errors.New("notarytool returned no submission ID")
+	}
+	return id, nil
+}
+
+func (backend notarytoolBackend) status(id string) (string, error) {
+	output, err := backend.run("info", id)
+	if err != nil {
+		return "", err
+	}
+	status, _ := output.get("status").(string)
+	return status, nil
+}
+
+func (backend notarytoolBackend) log(id string) ([]byte, error) {
+	commandArgs := append([]string{"notarytool", "log", id}, backend.credentials...)
+	output, err := base.ExecCommandInWorkingDir("xcrun", commandArgs, "")
+	if err != nil {
+		return nil, err
+	}
+	// Skip the messages printed before the JSON.
+	if start := bytes.IndexByte(output, '{'); start > 0 {
+		output = output[start:]
+	}
+	return output, nil
+}
+
+// run runs the notarytool subcommand and parses its plist output.
+func (backend notarytoolBackend) run(subcommand, argument string) (*plistDict, error) {
+	commandArgs := []string{"notarytool", subcommand, argument}
+	commandArgs = append(commandArgs, backend.credentials...)
+	commandArgs = append(commandArgs, "--output-format", "plist")
 	output, err := base.ExecCommandInWorkingDir("xcrun", commandArgs, "")
+	if err != nil {
+		return nil, err
+	}
+	// Skip the messages printed before the plist.
+	if start := bytes.Index(output, []byte("<?xml")); start > 0 {
+		output = output[start:]
+	}
+	parsed, err := parsePlist(output)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the output of notarytool %s: %w", subcommand, err)
+	}
+	dict, ok := parsed.(*plistDict)
+	if !ok {
+		return nil, fmt.Errorf("notarytool %s returned no dictionary", subcommand)
+	}
+	return dict, nil
+}
+
+// waitForNotarization polls the status of the submission until it is not
+// in progress anymore, and returns the final status.
+func waitForNotarization(backend notaryBackend, id string) (string, error) {
+	delay := notaryPollDelay
+	deadline := time.Now().Add(notaryTimeout)
+	for {
+		status, err := backend.status(id)
+		if err != nil {
+			return "", err
+		}
+		if status != notaryStatusInProgress {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("the submission %s is still in progress after %s", id, notaryTimeout)
+		}
+		base.Logf("The submission %s is in progress, checking again in %s", id, delay)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > notaryMaxPollDelay {
+			delay = notaryMaxPollDelay
+		}
+	}
+}
+
+// submitApp archives the app bundle in the output directory, submits
+// the archive, and returns the submission ID.
+func submitApp(backend notaryBackend, outDirPath string, appBundleName string) (string, error) {
+	appBundleZip, err := archiveApp(appBundleName, outDirPath)
+	if err != nil {
+		return "", err
+	}
+
+	defer func(name string) {
+		if base.PathExists(name) {
+			_ = os.Remove(name)
+		}
+	}(appBundleZip)
+
+	if _, err := os.Stat(appBundleZip); err != nil {
+		return "", err
+	}
+
+	base.Log("Notarizing " + appBundleZip + " (it may take a while)...")
+	id, err := backend.submit(appBundleZip)
+	if err != nil {
+		return "", err
+	}
+	base.Log("The submission ID is " + id)
+	return id, nil
+}
+
+// finishNotarization waits for the result of the submission, then verifies
+// the app bundle and staples the ticket to it. If the app is not accepted,
+// the notarization log is printed and saved next to the output directory.
+func finishNotarization(backend notaryBackend, id string, outDirPath string, appBundleName string) error {
+	status, err := waitForNotarization(backend, id)
 	if err != nil {
 		return err
-	} else if !strings.Contains(string(output), "<string>Accepted</string>") {
-		return errors.New("failed to notarize the application. The status is not \"Accepted\"")
+	}
+	if status != notaryStatusAccepted {
+		reportNotaryLog(backend, id, outDirPath+"-notarization-log.json")
+		return fmt.Errorf("failed to notarize the application. The status of the submission %s is %q", id, status)
 	}
 	base.Log("The application has been notarized successfully")
+
+	appBundlePath := filepath.Join(outDirPath, appBundleName)
+	if err := verify(appBundlePath); err != nil {
+		return err
+	}
+
+	if err := stapleTicket(appBundlePath); err != nil {
+		return err
+	}
+
+	if err := validateStapling(appBundlePath); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -112,8 +295,60 @@ func validateStapling(appPath string) error {
 	return nil
 }
 
-// ValidateNotarizationParams checks if the notarization parameters are set.
+// UsesAppleIdNotarization reports whether the app bundle is notarized with
+// the Apple ID, the app-specific password, and the team ID rather than with
+// an API key or a keychain profile.
+func UsesAppleIdNotarization(params common.BrandingParams) bool {
+	notarization := params.Mac.Notarization
+	return notarization == nil || (notarization.ApiKey == "" && notarization.KeychainProfile == "")
+}
+
+// ValidateNotarizationParams checks if the parameters of the chosen
+// notarization credentials are set, and if the notary backend can be created
+// with them.
 func ValidateNotarizationParams(params common.BrandingParams) error {
+	credentials, err := notaryCredentials(params)
+	if err != nil {
+		return err
+	}
+	_, err = newNotaryBackend(params, credentials)
+	return err
+}
+
+// notaryCredentials returns the notarytool arguments passing the credentials
+// chosen in the parameters: a keychain profile, an API key, or an Apple ID.
+func notaryCredentials(params common.BrandingParams) ([]string, error) {
+	notarization := params.Mac.Notarization
+	if notarization != nil && notarization.KeychainProfile != "" {
+		if notarization.ApiKey != "" {
+			return nil, errors.New("mac.notarization.apiKey and mac.notarization.keychainProfile cannot be used together")
+		}
+		profile := base.GetValue(notarization.KeychainProfile)
+		if profile == "" {
+			return nil, errors.New("mac.notarization.keychainProfile is empty")
+		}
+		return []string{"--keychain-profile", profile}, nil
+	}
+
+	if notarization != nil && notarization.ApiKey != "" {
+		key := base.GetValue(notarization.ApiKey)
+		keyID := base.GetValue(notarization.ApiKeyId)
+		if key == "" {
+			return nil, errors.New("mac.notarization.apiKey is empty")
+		}
+		if keyID == "" {
+			return nil, errors.New("mac.notarization.apiKeyId is empty")
+		}
+		if !base.PathExists(key) {
+			return nil, errors.New("the API key " + key + " does not exist")
+		}
+		credentials := []string{"--key", key, "--key-id", keyID}
+		if issuer := base.GetValue(notarization.ApiIssuer); issuer != "" {
+			credentials = append(credentials, "--issuer", issuer)
+		}
+		return credentials, nil
+	}
+
 	p := map[string]string{
 		"Team ID":  base.GetValue(params.Mac.TeamId),
 		"Apple ID": base.GetValue(params.Mac.AppleId),
@@ -121,58 +356,37 @@ func ValidateNotarizationParams(params common.BrandingParams) error {
 	}
 	for paramName, paramValue := range p {
 		if paramValue == "" {
-			return errors.New(paramName + " is empty")
+			return nil, errors.New(paramName + " is empty")
 		}
 	}
-	return nil
+	return []string{
+		"--team-id", p["Team ID"],
+		"--apple-id", p["Apple ID"],
+		"--password", p["Password"]}, nil
 }
 
 // Notarize notarizes the application bundle with the provided parameters.
 func Notarize(outDir string, params common.BrandingParams) (bool, error) {
-	err := ValidateNotarizationParams(params)
+	credentials, err := notaryCredentials(params)
 	if err != nil {
 		return false, nil
 	}
+	backend, err := newNotaryBackend(params, credentials)
+	if err != nil {
+		return false, err
+	}
 
 	outDirPath, err := filepath.Abs(outDir)
 	if err != nil {
 		return false, err
 	}
 	appBundleName := *params.Mac.Bundle.Name + ".app"
-	appBundlePath := filepath.Join(outDirPath, appBundleName)
-	appBundleZip, err := archiveApp(appBundleName, outDirPath)
+	id, err := submitApp(backend, outDirPath, appBundleName)
 	if err != nil {
 		return false, err
 	}
 
-	defer func(name string) {
-		if base.PathExists(name) {
-			_ = os.Remove(name)
-		}
-	}(appBundleZip)
-
-	if _, err := os.Stat(appBundleZip); err != nil {
-		return false, err
-	}
-
-	if err := notarize(
-		appBundleZip,
-		base.GetValue(params.Mac.TeamId),
-		base.GetValue(params.Mac.AppleId),
-		base.GetValue(params.Mac.Password),
-	); err != nil {
-		return false, err
-	}
-
-	if err := verify(appBundlePath); err != nil {
-		return false, err
-	}
-
-	if err := stapleTicket(appBundlePath); err != nil {
-		return false, err
-	}
-
-	if err := validateStapling(appBundlePath); err != nil {
+	if err := finishNotarization(backend, id, outDirPath, appBundleName); err != nil {
 		return false, err
 	}
 
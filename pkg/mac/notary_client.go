@@ -0,0 +1,287 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+const (
+	defaultNotaryURL    = "https://appstoreconnect.apple.com/notary/v2"
+	notaryAudience      = "appstoreconnect-v1"
+	notaryTokenLifetime = 15 * time.Minute
+)
+
+// notaryHTTPClient is the HTTP client used to talk to the Notary API
+// and to upload the archives.
+var notaryHTTPClient = &http.Client{Timeout: 30 * time.Minute}
+
+// notaryClient is a client of Apple's Notary REST API authenticated with
+// an App Store Connect API key.
+type notaryClient struct {
+	baseURL   string
+	uploadURL string
+	keyID     string
+	issuer    string
+	key       *ecdsa.PrivateKey
+}
+
+type notaryResponse struct {
+	Data struct {
+		Id         string `json:"id"`
+		Attributes struct {
+			Status             string `json:"status"`
+			DeveloperLogUrl    string `json:"developerLogUrl"`
+			AwsAccessKeyId     string `json:"awsAccessKeyId"`
+			AwsSecretAccessKey string `json:"awsSecretAccessKey"`
+			AwsSessionToken    string `json:"awsSessionToken"`
+			Bucket             string `json:"bucket"`
+			Object             string `json:"object"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type notaryErrorResponse struct {
+	Errors []struct {
+		Code   string `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// newNotaryClient creates a client of the Notary API at baseURL, Apple's
+// service if empty, that uploads the archives to uploadURL, Amazon S3 if empty.
+func newNotaryClient(baseURL, uploadURL, keyPath, keyID, issuer string) (*notaryClient, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading the API key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM-encoded API key", keyPath)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", keyPath, err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an App Store Connect API key", keyPath)
+	}
+	if baseURL == "" {
+		baseURL = defaultNotaryURL
+	}
+	return &notaryClient{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		uploadURL: strings.TrimSuffix(uploadURL, "/"),
+		keyID:     keyID,
+		issuer:    issuer,
+		key:       key,
+	}, nil
+}
+
+// token creates a JSON Web Token signed with the API key. Team keys are
+// identified by the issuer, and individual keys by the "user" subject.
+func (client *notaryClient) token() (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": client.keyID, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]any{
+		"iat": now.Unix(),
+		"exp": now.Add(notaryTokenLifetime).Unix(),
+		"aud": notaryAudience,
+	}
+	if client.issuer != "" {
+		claims["iss"] = client.issuer
+	} else {
+		claims["sub"] = "user"
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	signingInput := encoding.EncodeToString(header) + "." + encoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, client.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// ES256 signatures are the 32-byte big-endian r and s concatenated.
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + encoding.EncodeToString(signature), nil
+}
+
+// call sends the request to the Notary API and decodes the response.
+func (client *notaryClient) call(method, path string, body any) (*notaryResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequest(method, client.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	token, err := client.token()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := notaryHTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("notary service: %w", err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("notary service: %w", err)
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, notaryError(response.Status, data)
+	}
+	result := &notaryResponse{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("notary service: invalid response: %w", err)
+	}
+	return result, nil
+}
+
+func notaryError(status string, body []byte) error {
+	var parsed notaryErrorResponse
+	if json.Unmarshal(body, &parsed) == nil && len(parsed.Errors) != 0 {
+		messages := []string{}
+		for _, e := range parsed.Errors {
+			messages = append(messages, strings.TrimSpace(e.Title+". "+e.Detail))
+		}
+		return fmt.Errorf("notary service: %s: %s", status, strings.Join(messages, "; "))
+	}
+	return fmt.Errorf("notary service: %s", status)
+}
+
+// submit creates a submission of the archive, uploads the archive,
+// and returns the submission ID.
+func (client *notaryClient) submit(archivePath string) (string, error) {
+	hash, err := fileSHA256(archivePath)
+	if err != nil {
+		return "", err
+	}
+	submission, err := client.call(http.MethodPost, "/submissions", map[string]string{
+		"submissionName": filepath.Base(archivePath),
+		"sha256":         hash,
+	})
+	if err != nil {
+		return "", err
+	}
+	attributes := submission.Data.Attributes
+	if submission.Data.Id == "" || attributes.Bucket == "" || attributes.Object == "" {
+		return "", errors.New("notary service: the submission has no ID or upload location")
+	}
+
+	base.Logf("Uploading %s for the submission %s", archivePath, submission.Data.Id)
+	upload := s3Upload{
+		endpoint:        client.uploadURL,
+		bucket:          attributes.Bucket,
+		object:          attributes.Object,
+		accessKeyID:     attributes.AwsAccessKeyId,
+		secretAccessKey: attributes.AwsSecretAccessKey,
+		sessionToken:    attributes.AwsSessionToken,
+	}
+	if err := upload.put(archivePath, hash); err != nil {
+		return "", err
+	}
+	return submission.Data.Id, nil
+}
+
+// status returns the status of the submission: "In Progress", "Accepted",
+// "Invalid", or "Rejected".
+func (client *notaryClient) status(id string) (string, error) {
+	submission, err := client.call(http.MethodGet, "/submissions/"+url.PathEscape(id), nil)
+	if err != nil {
+		return "", err
+	}
+	return submission.Data.Attributes.Status, nil
+}
+
+// log downloads the JSON log of the submission.
+func (client *notaryClient) log(id string) ([]byte, error) {
+	submission, err := client.call(http.MethodGet, "/submissions/"+url.PathEscape(id)+"/logs", nil)
+	if err != nil {
+		return nil, err
+	}
+	logURL := submission.Data.Attributes.DeveloperLogUrl
+	if logURL == "" {
+		return nil, errors.New("notary service: the submission has no log")
+	}
+	response, err := notaryHTTPClient.Get(logURL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading the notarization log: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading the notarization log: %s", response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
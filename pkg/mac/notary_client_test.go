@@ -0,0 +1,307 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testKeyID        = "KEY1234567"
+	testIssuer       = "57246542-96fe-1a63-e053-0824d011072a"
+	testSubmissionID = "2efe2717-52ef-43a5-96dc-0797e4ca1041"
+	testBucket       = "notary-submissions-prod"
+	testObject       = "prod/AAAA/2efe2717 App.zip"
+	testAccessKeyID  = "ASIAEXAMPLE"
+	testSecretKey    = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	testSessionToken = "session-token"
+	testNotaryLog    = `{"jobId":"2efe2717-52ef-43a5-96dc-0797e4ca1041","status":"Invalid","archiveFilename":"App.zip","issues":[{"severity":"error","path":"App.zip/App.app/Contents/MacOS/App","message":"The signature does not include a secure timestamp."}]}`
+)
+
+// testNotaryService is a stand-in for the Notary API and Amazon S3.
+type testNotaryService struct {
+	t      *testing.T
+	key    *ecdsa.PublicKey
+	server *httptest.Server
+
+	mutex      sync.Mutex
+	archive    []byte
+	statusGets int
+}
+
+func newTestNotaryService(t *testing.T, key *ecdsa.PublicKey) *testNotaryService {
+	service := &testNotaryService{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/notary/v2/submissions", service.handleSubmit)
+	mux.HandleFunc("/notary/v2/submissions/"+testSubmissionID, service.handleStatus)
+	mux.HandleFunc("/notary/v2/submissions/"+testSubmissionID+"/logs", service.handleLogs)
+	mux.HandleFunc("/s3/", service.handleUpload)
+	mux.HandleFunc("/log.json", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, testNotaryLog)
+	})
+	service.server = httptest.NewServer(mux)
+	t.Cleanup(service.server.Close)
+	return service
+}
+
+// fail reports the error to the test and to the client.
+func (service *testNotaryService) fail(w http.ResponseWriter, format string, args ...any) {
+	service.t.Errorf(format, args...)
+	http.Error(w, fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+// checkToken verifies the JSON Web Token of the request.
+func (service *testNotaryService) checkToken(r *http.Request) error {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid token %q", token)
+	}
+	var header, claims map[string]any
+	for i, value := range []*map[string]any{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, value); err != nil {
+			return err
+		}
+	}
+	if header["alg"] != "ES256" || header["kid"] != testKeyID {
+		return fmt.Errorf("unexpected token header %v", header)
+	}
+	if claims["iss"] != testIssuer || claims["aud"] != notaryAudience {
+		return fmt.Errorf("unexpected token claims %v", claims)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return fmt.Errorf("invalid token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r1, s1 := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(service.key, digest[:], r1, s1) {
+		return fmt.Errorf("the token signature does not match the API key")
+	}
+	return nil
+}
+
+func (service *testNotaryService) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := service.checkToken(r); err != nil {
+		service.fail(w, "submit: %v", err)
+		return
+	}
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Method != http.MethodPost {
+		service.fail(w, "submit: invalid request %s: %v", r.Method, err)
+		return
+	}
+	if body["submissionName"] != "App.zip" || len(body["sha256"]) != 64 {
+		service.fail(w, "submit: unexpected body %v", body)
+		return
+	}
+	fmt.Fprintf(w, `{"data":{"id":%q,"type":"newSubmissions","attributes":{
+		"awsAccessKeyId":%q,"awsSecretAccessKey":%q,"awsSessionToken":%q,"bucket":%q,"object":%q}}}`,
+		testSubmissionID, testAccessKeyID, testSecretKey, testSessionToken, testBucket, testObject)
+}
+
+func (service *testNotaryService) handleUpload(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.Method != http.MethodPut {
+		service.fail(w, "upload: invalid request %s: %v", r.Method, err)
+		return
+	}
+	if r.URL.Path != "/s3/"+testBucket+"/"+testObject {
+		service.fail(w, "upload: unexpected path %s", r.URL.Path)
+		return
+	}
+	payloadHash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(payloadHash[:]) {
+		service.fail(w, "upload: the payload hash %s does not match the body", r.Header.Get("X-Amz-Content-Sha256"))
+		return
+	}
+	if r.Header.Get("X-Amz-Security-Token") != testSessionToken {
+		service.fail(w, "upload: unexpected session token %q", r.Header.Get("X-Amz-Security-Token"))
+		return
+	}
+	if expected := testSigV4Authorization(r); r.Header.Get("Authorization") != expected {
+		service.fail(w, "upload: the authorization is\n%s\nexpected\n%s", r.Header.Get("Authorization"), expected)
+		return
+	}
+	service.mutex.Lock()
+	service.archive = body
+	service.mutex.Unlock()
+}
+
+func (service *testNotaryService) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if err := service.checkToken(r); err != nil {
+		service.fail(w, "status: %v", err)
+		return
+	}
+	service.mutex.Lock()
+	service.statusGets++
+	status := notaryStatusInProgress
+	if service.statusGets > 2 {
+		status = "Invalid"
+	}
+	service.mutex.Unlock()
+	fmt.Fprintf(w, `{"data":{"id":%q,"attributes":{"status":%q}}}`, testSubmissionID, status)
+}
+
+func (service *testNotaryService) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if err := service.checkToken(r); err != nil {
+		service.fail(w, "logs: %v", err)
+		return
+	}
+	fmt.Fprintf(w, `{"data":{"id":%q,"attributes":{"developerLogUrl":%q}}}`, testSubmissionID, service.server.URL+"/log.json")
+}
+
+// testSigV4Authorization computes the Authorization header Amazon S3 expects
+// for the request, following the AWS Signature Version 4 specification.
+func testSigV4Authorization(r *http.Request) string {
+	amzDate := r.Header.Get("X-Amz-Date")
+	date := amzDate[:8]
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date;x-amz-security-token"
+	canonicalRequest := "PUT\n" +
+		r.URL.EscapedPath() + "\n" +
+		"\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:" + r.Header.Get("X-Amz-Content-Sha256") + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"x-amz-security-token:" + r.Header.Get("X-Amz-Security-Token") + "\n" +
+		"\n" +
+		signedHeaders + "\n" +
+		r.Header.Get("X-Amz-Content-Sha256")
+	scope := date + "/us-west-2/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, "us-west-2", "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	return "AWS4-HMAC-SHA256 Credential=" + testAccessKeyID + "/" + scope +
+		", SignedHeaders=" + signedHeaders + ", Signature=" + hex.EncodeToString(key)
+}
+
+// writeTestAPIKey writes the key as an App Store Connect API key file.
+func writeTestAPIKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "AuthKey_"+testKeyID+".p8")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNotaryClientSubmitsAndReportsLog(t *testing.T) {
+	savedPollDelay := notaryPollDelay
+	notaryPollDelay = time.Millisecond
+	defer func() { notaryPollDelay = savedPollDelay }()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newTestNotaryService(t, &key.PublicKey)
+	client, err := newNotaryClient(service.server.URL+"/notary/v2", service.server.URL+"/s3",
+		writeTestAPIKey(t, key), testKeyID, testIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "App.zip")
+	if err := os.WriteFile(archive, []byte("archive content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := client.submit(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != testSubmissionID {
+		t.Fatalf("the submission ID is %q, expected %s", id, testSubmissionID)
+	}
+	if string(service.archive) != "archive content" {
+		t.Fatalf("the uploaded archive is %q", service.archive)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "out")
+	err = finishNotarization(client, id, outDir, "App.app")
+	if err == nil || !strings.Contains(err.Error(), `"Invalid"`) {
+		t.Fatalf("expected the invalid submission error, got %v", err)
+	}
+	if service.statusGets != 3 {
+		t.Errorf("the status is checked %d times, expected 3", service.statusGets)
+	}
+	log, err := os.ReadFile(outDir + "-notarization-log.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(log) != testNotaryLog {
+		t.Errorf("the saved log is %s", log)
+	}
+}
+
+func TestNotaryClientReportsServiceErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"errors":[{"code":"NOT_AUTHORIZED","title":"Authentication failure","detail":"Failure to authenticate"}]}`)
+	}))
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newNotaryClient(server.URL, "", writeTestAPIKey(t, key), testKeyID, testIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.status(testSubmissionID)
+	if err == nil || !strings.Contains(err.Error(), "Authentication failure. Failure to authenticate") {
+		t.Fatalf("expected the authentication error, got %v", err)
+	}
+}
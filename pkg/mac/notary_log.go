@@ -0,0 +1,91 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// notaryLog is the part of the notarization log of a submission
+// that is printed.
+type notaryLog struct {
+	JobId           string        `json:"jobId"`
+	Status          string        `json:"status"`
+	StatusSummary   string        `json:"statusSummary"`
+	ArchiveFilename string        `json:"archiveFilename"`
+	Issues          []notaryIssue `json:"issues"`
+}
+
+type notaryIssue struct {
+	Severity     string `json:"severity"`
+	Path         string `json:"path"`
+	Architecture string `json:"architecture"`
+	Message      string `json:"message"`
+	DocUrl       string `json:"docUrl"`
+}
+
+// reportNotaryLog fetches the notarization log of the submission, prints
+// its issues, and saves it to logPath. The failures are printed as warnings
+// as the log is not essential.
+func reportNotaryLog(backend notaryBackend, id, logPath string) {
+	data, err := backend.log(id)
+	if err != nil {
+		fmt.Printf("WARNING: cannot fetch the notarization log of the submission %s: %v\n", id, err)
+		return
+	}
+	if err := os.WriteFile(logPath, data, 0644); err != nil {
+		fmt.Printf("WARNING: cannot save the notarization log: %v\n", err)
+	} else {
+		fmt.Println("The notarization log is saved to " + logPath)
+	}
+
+	var log notaryLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		fmt.Printf("WARNING: cannot parse the notarization log: %v\n", err)
+		return
+	}
+	fmt.Print(log.String())
+}
+
+// String formats the status and the issues of the log, one issue per line,
+// with the paths relative to the archive.
+func (log notaryLog) String() string {
+	var text strings.Builder
+	text.WriteString("Notarization status: " + log.Status)
+	if log.StatusSummary != "" {
+		text.WriteString(" (" + log.StatusSummary + ")")
+	}
+	text.WriteString("\n")
+	for _, issue := range log.Issues {
+		path := strings.TrimPrefix(issue.Path, log.ArchiveFilename+"/")
+		if issue.Architecture != "" {
+			path += " (" + issue.Architecture + ")"
+		}
+		fmt.Fprintf(&text, "  %s: %s: %s\n", issue.Severity, path, issue.Message)
+		if issue.DocUrl != "" {
+			text.WriteString("    See " + issue.DocUrl + "\n")
+		}
+	}
+	return text.String()
+}
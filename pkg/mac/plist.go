@@ -0,0 +1,268 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const plistHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// plistDict is a property list dictionary that keeps the order of its keys.
+//
+// The values of the property lists are represented by *plistDict, []any,
+// string, int64, float64, bool, []byte, and time.Time.
+type plistDict struct {
+	keys   []string
+	values map[string]any
+}
+
+func newPlistDict() *plistDict {
+	return &plistDict{values: map[string]any{}}
+}
+
+// get returns the value of the key, or nil if there is no such key.
+func (dict *plistDict) get(key string) any {
+	return dict.values[key]
+}
+
+// has reports whether the dictionary contains the key.
+func (dict *plistDict) has(key string) bool {
+	_, ok := dict.values[key]
+	return ok
+}
+
+// set adds the key or replaces its value keeping its position.
+func (dict *plistDict) set(key string, value any) {
+	if !dict.has(key) {
+		dict.keys = append(dict.keys, key)
+	}
+	dict.values[key] = value
+}
+
+// remove removes the key if present.
+func (dict *plistDict) remove(key string) {
+	if !dict.has(key) {
+		return
+	}
+	delete(dict.values, key)
+	for i, existing := range dict.keys {
+		if existing == key {
+			dict.keys = append(dict.keys[:i], dict.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// parsePlist parses an XML property list.
+func parsePlist(data []byte) (any, error) {
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		return nil, errors.New("binary property lists are not supported, convert it with `plutil -convert xml1`")
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("no plist element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "plist" {
+			return nil, fmt.Errorf("unexpected <%s> element, expected <plist>", start.Name.Local)
+		}
+		value, end, err := parsePlistValue(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if end {
+			return nil, errors.New("empty plist element")
+		}
+		return value, nil
+	}
+}
+
+// parsePlistValue parses the next value. Returns true instead if the end
+// of the enclosing element comes first.
+func parsePlistValue(decoder *xml.Decoder) (any, bool, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			return nil, true, nil
+		case xml.StartElement:
+			value, err := parsePlistElement(decoder, t)
+			return value, false, err
+		}
+	}
+}
+
+func parsePlistElement(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := newPlistDict()
+		for {
+			key, end, err := parsePlistValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return dict, nil
+			}
+			keyString, ok := key.(plistKey)
+			if !ok {
+				return nil, errors.New("a dict value without a key")
+			}
+			value, end, err := parsePlistValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return nil, fmt.Errorf("no value for the key %q", keyString)
+			}
+			dict.set(string(keyString), value)
+		}
+	case "array":
+		array := []any{}
+		for {
+			value, end, err := parsePlistValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return array, nil
+			}
+			array = append(array, value)
+		}
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := decoder.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "key":
+		return plistKey(text), nil
+	case "string":
+		return text, nil
+	case "integer":
+		return strconv.ParseInt(strings.TrimSpace(text), 0, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	case "date":
+		return time.Parse(time.RFC3339, strings.TrimSpace(text))
+	default:
+		return nil, fmt.Errorf("unknown plist element <%s>", start.Name.Local)
+	}
+}
+
+// plistKey is the text of a <key> element, distinguished from <string>.
+type plistKey string
+
+// encodePlist writes the value as an XML property list indented with tabs,
+// as Xcode does.
+func encodePlist(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(plistHeader)
+	if err := encodePlistValue(&buffer, value, 0); err != nil {
+		return nil, err
+	}
+	buffer.WriteString("</plist>\n")
+	return buffer.Bytes(), nil
+}
+
+func encodePlistValue(buffer *bytes.Buffer, value any, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	switch v := value.(type) {
+	case *plistDict:
+		if len(v.keys) == 0 {
+			buffer.WriteString(indent + "<dict/>\n")
+			return nil
+		}
+		buffer.WriteString(indent + "<dict>\n")
+		for _, key := range v.keys {
+			buffer.WriteString(indent + "\t<key>" + escapePlistText(key) + "</key>\n")
+			if err := encodePlistValue(buffer, v.values[key], depth+1); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString(indent + "</dict>\n")
+	case []any:
+		if len(v) == 0 {
+			buffer.WriteString(indent + "<array/>\n")
+			return nil
+		}
+		buffer.WriteString(indent + "<array>\n")
+		for _, item := range v {
+			if err := encodePlistValue(buffer, item, depth+1); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString(indent + "</array>\n")
+	case string:
+		buffer.WriteString(indent + "<string>" + escapePlistText(v) + "</string>\n")
+	case int64:
+		buffer.WriteString(indent + "<integer>" + strconv.FormatInt(v, 10) + "</integer>\n")
+	case float64:
+		buffer.WriteString(indent + "<real>" + strconv.FormatFloat(v, 'g', -1, 64) + "</real>\n")
+	case bool:
+		buffer.WriteString(indent + "<" + strconv.FormatBool(v) + "/>\n")
+	case []byte:
+		buffer.WriteString(indent + "<data>" + base64.StdEncoding.EncodeToString(v) + "</data>\n")
+	case time.Time:
+		buffer.WriteString(indent + "<date>" + v.UTC().Format(time.RFC3339) + "</date>\n")
+	default:
+		return fmt.Errorf("unsupported plist value of type %T", value)
+	}
+	return nil
+}
+
+// plistTextEscaper escapes the text as Apple's tools do, keeping the quotes.
+var plistTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapePlistText(text string) string {
+	return plistTextEscaper.Replace(text)
+}
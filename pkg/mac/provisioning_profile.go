@@ -0,0 +1,176 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	applicationIdentifierEntitlement     = "com.apple.application-identifier"
+	iosApplicationIdentifierEntitlement  = "application-identifier"
+	provisioningProfileExpirationWarning = 30 * 24 * time.Hour
+)
+
+// ProvisioningProfile is the content of a provisioning profile that matters
+// for signing the app bundle.
+type ProvisioningProfile struct {
+	Name            string
+	UUID            string
+	TeamIdentifiers []string
+	ExpirationDate  time.Time
+
+	// ApplicationIdentifier is the team ID followed by the bundle ID,
+	// which may end with a wildcard, e.g. "ABCDE12345.com.example.*".
+	ApplicationIdentifier string
+
+	// KeychainAccessGroups are the allowed keychain access groups, which
+	// may end with a wildcard.
+	KeychainAccessGroups []string
+}
+
+// ReadProvisioningProfile decodes the CMS-wrapped provisioning profile
+// and checks its signature.
+func ReadProvisioningProfile(path string) (*ProvisioningProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading provisioning profile: %w", err)
+	}
+	signedData, err := base.ParseSignedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a provisioning profile: %w", path, err)
+	}
+	if err := signedData.VerifySignature(); err != nil {
+		return nil, fmt.Errorf("the signature of the provisioning profile %s is invalid: %w", path, err)
+	}
+	value, err := parsePlist(signedData.Content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing provisioning profile %s: %w", path, err)
+	}
+	dict, ok := value.(*plistDict)
+	if !ok {
+		return nil, fmt.Errorf("the provisioning profile %s is not a dictionary", path)
+	}
+
+	profile := &ProvisioningProfile{}
+	profile.Name, _ = dict.get("Name").(string)
+	profile.UUID, _ = dict.get("UUID").(string)
+	profile.ExpirationDate, _ = dict.get("ExpirationDate").(time.Time)
+	profile.TeamIdentifiers = plistStrings(dict.get("TeamIdentifier"))
+	if entitlements, ok := dict.get("Entitlements").(*plistDict); ok {
+		profile.ApplicationIdentifier, _ = entitlements.get(applicationIdentifierEntitlement).(string)
+		if profile.ApplicationIdentifier == "" {
+			profile.ApplicationIdentifier, _ = entitlements.get(iosApplicationIdentifierEntitlement).(string)
+		}
+		profile.KeychainAccessGroups = plistStrings(entitlements.get(keychainAccessGroupsEntitlement))
+	}
+	return profile, nil
+}
+
+// Check reports the problems that make the profile unusable for the app
+// signed with the given entitlements: the profile has expired, belongs to
+// another team, is made for another bundle ID, or does not allow
+// the keychain access groups of the app.
+func (profile *ProvisioningProfile) Check(params common.BrandingParams, appEntitlements string) error {
+	problems := []error{}
+
+	if profile.ExpirationDate.IsZero() {
+		problems = append(problems, errors.New("the profile has no expiration date"))
+	} else if time.Now().After(profile.ExpirationDate) {
+		problems = append(problems, fmt.Errorf("the profile expired on %s", profile.ExpirationDate.Format(time.DateOnly)))
+	} else if time.Until(profile.ExpirationDate) < provisioningProfileExpirationWarning {
+		fmt.Printf("WARNING: the provisioning profile %s expires on %s\n", profile.Name, profile.ExpirationDate.Format(time.DateOnly))
+	}
+
+	if teamID := base.GetValue(params.Mac.TeamId); teamID != "" && !base.Contains(profile.TeamIdentifiers, teamID) {
+		problems = append(problems, fmt.Errorf("the profile belongs to the team %s rather than mac.teamID %s",
+			strings.Join(profile.TeamIdentifiers, ", "), teamID))
+	}
+
+	if params.Mac.Bundle != nil && params.Mac.Bundle.Id != nil {
+		bundleID := *params.Mac.Bundle.Id
+		_, profileBundleID, _ := strings.Cut(profile.ApplicationIdentifier, ".")
+		if !matchesEntitlementPattern(profileBundleID, bundleID) {
+			problems = append(problems, fmt.Errorf("the profile is made for the app identifier %s that does not match mac.bundle.id %s",
+				profile.ApplicationIdentifier, bundleID))
+		}
+	}
+
+	entitlements, err := readEntitlements(appEntitlements)
+	if err != nil {
+		problems = append(problems, err)
+	} else {
+		for _, group := range plistStrings(entitlements.get(keychainAccessGroupsEntitlement)) {
+			if !profile.allowsKeychainAccessGroup(group) {
+				problems = append(problems, fmt.Errorf("the keychain access group %s is not allowed by the profile, which allows %s",
+					group, strings.Join(profile.KeychainAccessGroups, ", ")))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the provisioning profile %s (%s) cannot be used:\n%w", profile.Name, profile.UUID, errors.Join(problems...))
+	}
+	return nil
+}
+
+func (profile *ProvisioningProfile) allowsKeychainAccessGroup(group string) bool {
+	for _, allowed := range profile.KeychainAccessGroups {
+		if matchesEntitlementPattern(allowed, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEntitlementPattern reports whether the value matches the pattern
+// of a provisioning profile, which may end with the "*" wildcard.
+func matchesEntitlementPattern(pattern, value string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+	return pattern == value
+}
+
+// plistStrings returns the strings of a plist array, or the string itself.
+func plistStrings(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		result := []string{}
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// notaryUploadRegion is the AWS region of the bucket the archives
+// to notarize are uploaded to.
+const notaryUploadRegion = "us-west-2"
+
+// s3Upload uploads a file to Amazon S3 with the temporary credentials
+// given by the Notary API, signing the request with AWS Signature Version 4.
+type s3Upload struct {
+	// endpoint is the URL of an S3-compatible service addressed in the path
+	// style. The virtual-hosted Amazon S3 endpoint is used if empty.
+	endpoint        string
+	bucket          string
+	object          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+func (upload s3Upload) url() string {
+	object := escapeS3Path(upload.object)
+	if upload.endpoint != "" {
+		return upload.endpoint + "/" + escapeS3Path(upload.bucket) + "/" + object
+	}
+	return "https://" + upload.bucket + ".s3." + notaryUploadRegion + ".amazonaws.com/" + object
+}
+
+// put uploads the file whose hex-encoded SHA-256 hash is payloadHash.
+func (upload s3Upload) put(path, payloadHash string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPut, upload.url(), file)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = info.Size()
+	upload.sign(request, payloadHash, time.Now().UTC())
+
+	response, err := notaryHTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", path, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return fmt.Errorf("uploading %s: %s %s", path, response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request.
+func (upload s3Upload) sign(request *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	request.Header.Set("X-Amz-Date", amzDate)
+	if upload.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", upload.sessionToken)
+	}
+
+	headers := map[string]string{
+		"host":                 request.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if upload.sessionToken != "" {
+		headers["x-amz-security-token"] = upload.sessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + notaryUploadRegion + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+upload.secretAccessKey), date)
+	key = hmacSHA256(key, notaryUploadRegion)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+upload.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapeS3Path percent-encodes every byte of the S3 object key except
+// the unreserved characters and the slashes, as AWS requires.
+func escapeS3Path(path string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(path) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
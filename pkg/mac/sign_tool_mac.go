@@ -30,17 +30,32 @@ import (
 // To create one, use `GetSignToolMac`.
 type SignToolMac struct {
 	params common.BrandingParams
+
+	// goSigner signs the binaries instead of codesign if set.
+	goSigner *goCodeSigner
 }
 
-// Creates a new sign tool for macOS.
+// Creates a new sign tool for macOS. The tool signs the binaries in Go
+// rather than with codesign if `UsesGoCodeSigner` tells so.
 func GetSignToolMac(params common.BrandingParams) (*SignToolMac, error) {
-	return &SignToolMac{params}, nil
+	tool := &SignToolMac{params: params}
+	useGo, err := UsesGoCodeSigner(params)
+	if err != nil {
+		return nil, err
+	}
+	if useGo && IsSigningConfigured(params) {
+		tool.goSigner, err = newGoCodeSigner(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tool, nil
 }
 
 // Signs the Chromium binaries located at `binaryPath`.
 func (tool *SignToolMac) SignBinary(binaryPath string) error {
 	// Skip signing and verifying if the identity is not set.
-	if base.GetValue(tool.params.Mac.CodesignIdentity) == "" {
+	if !IsSigningConfigured(tool.params) {
 		return nil
 	}
 	if err := tool.sign(binaryPath, tool.params.Mac.CodesignEntitlements); err != nil {
@@ -56,7 +71,7 @@ func (tool *SignToolMac) SignBinary(binaryPath string) error {
 // Use this for helper bundles and dylibs that require different entitlements
 // than the main application.
 func (tool *SignToolMac) SignBinaryWithEntitlements(binaryPath, entitlements string) error {
-	if base.GetValue(tool.params.Mac.CodesignIdentity) == "" {
+	if !IsSigningConfigured(tool.params) {
 		return nil
 	}
 	if err := tool.sign(binaryPath, entitlements); err != nil {
@@ -69,6 +84,10 @@ func (tool *SignToolMac) SignBinaryWithEntitlements(binaryPath, entitlements str
 }
 
 func (tool *SignToolMac) sign(binaryPath, entitlements string) error {
+	if tool.goSigner != nil {
+		base.Logf("Signing %s in Go", binaryPath)
+		return tool.goSigner.sign(binaryPath, entitlements)
+	}
 	return base.ExecCommand("codesign",
 		[]string{
 			"--force",
@@ -82,6 +101,9 @@ func (tool *SignToolMac) sign(binaryPath, entitlements string) error {
 }
 
 func (tool *SignToolMac) verify(binaryPath string) error {
+	if tool.goSigner != nil {
+		return tool.goSigner.verify(binaryPath)
+	}
 	return base.ExecCommand("codesign",
 		[]string{
 			"-vvv",
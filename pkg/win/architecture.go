@@ -0,0 +1,125 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+const (
+	machineI386  = 0x014C
+	machineAMD64 = 0x8664
+	machineARM64 = 0xAA64
+)
+
+// machineArchitectures maps the supported PE machine types to the names
+// of the architectures as used in the Windows SDK bin directory.
+var machineArchitectures = map[uint16]string{
+	machineI386:  "x86",
+	machineAMD64: "x64",
+	machineARM64: "arm64",
+}
+
+// binaryArchitecture returns the architecture of the PE file, or an error
+// if the file is not a PE file or its machine type is not supported.
+func binaryArchitecture(path base.AbsPath) (string, error) {
+	pe, err := readPEFile(path.String())
+	if err != nil {
+		return "", err
+	}
+	architecture, ok := machineArchitectures[pe.machine]
+	if !ok {
+		return "", fmt.Errorf("%s has the unsupported machine type 0x%04X", path.String(), pe.machine)
+	}
+	return architecture, nil
+}
+
+// checkArchitecture prints the architecture of every binary and returns
+// an error if the binaries have different or unsupported architectures.
+func checkArchitecture(paths []base.AbsPath) error {
+	binariesByArchitecture := map[string][]string{}
+	for _, path := range paths {
+		architecture, err := binaryArchitecture(path)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Architecture of " + path.Base() + ": " + architecture)
+		binariesByArchitecture[architecture] = append(binariesByArchitecture[architecture], path.Base())
+	}
+
+	if len(binariesByArchitecture) > 1 {
+		descriptions := []string{}
+		for architecture, binaries := range binariesByArchitecture {
+			descriptions = append(descriptions, architecture+" ("+strings.Join(binaries, ", ")+")")
+		}
+		sort.Strings(descriptions)
+		return fmt.Errorf("the binaries have different architectures: %s", strings.Join(descriptions, "; "))
+	}
+	return nil
+}
+
+// hostArchitectures returns the architectures of the Windows SDK tools
+// that can run on this machine, the native one first. ARM64 Windows
+// runs the x64 and x86 tools under emulation.
+func hostArchitectures() []string {
+	switch nativeMachine() {
+	case machineARM64:
+		return []string{"arm64", "x64", "x86"}
+	case machineI386:
+		return []string{"x86"}
+	default:
+		return []string{"x64", "x86"}
+	}
+}
+
+// machineFromEnvironment returns the machine type of the host named by the
+// PROCESSOR_ARCHITEW6432 variable, which is set for a 32-bit process on 64-bit
+// Windows, or by PROCESSOR_ARCHITECTURE. Falls back to the architecture
+// the tool is built for.
+func machineFromEnvironment() uint16 {
+	architecture := os.Getenv("PROCESSOR_ARCHITEW6432")
+	if architecture == "" {
+		architecture = os.Getenv("PROCESSOR_ARCHITECTURE")
+	}
+	switch strings.ToUpper(architecture) {
+	case "ARM64":
+		return machineARM64
+	case "AMD64":
+		return machineAMD64
+	case "X86":
+		return machineI386
+	}
+
+	switch runtime.GOARCH {
+	case "arm64":
+		return machineARM64
+	case "386":
+		return machineI386
+	default:
+		return machineAMD64
+	}
+}
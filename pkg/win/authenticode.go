@@ -0,0 +1,171 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+)
+
+const (
+	winCertificateHeaderSize = 8
+	winCertificateTypePKCS7  = 0x0002
+)
+
+// spcIndirectDataContent is the Authenticode content signed by the publisher.
+type spcIndirectDataContent struct {
+	Data          asn1.RawValue
+	MessageDigest struct {
+		Algorithm pkix.AlgorithmIdentifier
+		Digest    []byte
+	}
+}
+
+// IsPEFile reports whether the file starts with the DOS and PE headers.
+// Only the headers are read, so it is cheap to call for every file of a directory.
+func IsPEFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, peHeaderOffsetField+4)
+	if _, err := io.ReadFull(file, header); err != nil || string(header[:2]) != dosSignature {
+		return false
+	}
+	signature := make([]byte, len(peSignature))
+	peOffset := int64(binary.LittleEndian.Uint32(header[peHeaderOffsetField:]))
+	if _, err := file.ReadAt(signature, peOffset); err != nil {
+		return false
+	}
+	return string(signature) == peSignature
+}
+
+// ValidSignerNames returns the common name and the organization of
+// the publisher who signed the PE file, or nil if the file is not signed.
+//
+// Returns an error if the signature does not match the file, or the publisher
+// certificate does not chain up to a trusted root at the time of signing.
+func ValidSignerNames(binaryPath string) ([]string, error) {
+	pe, err := readPEFile(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := pe.authenticodeSignature()
+	if signedData == nil || err != nil {
+		return nil, err
+	}
+	if err := pe.verifyAuthenticode(signedData); err != nil {
+		return nil, fmt.Errorf("%s: %w", binaryPath, err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the trusted root certificates: %w", err)
+	}
+	if err := signedData.VerifyChain(roots); err != nil {
+		return nil, fmt.Errorf("%s: %w", binaryPath, err)
+	}
+
+	names := []string{}
+	subject := signedData.Signer.Subject
+	if subject.CommonName != "" {
+		names = append(names, subject.CommonName)
+	}
+	for _, organization := range subject.Organization {
+		if !base.Contains(names, organization) {
+			names = append(names, organization)
+		}
+	}
+	return names, nil
+}
+
+// authenticodeSignature parses the first certificate of the security
+// directory, or returns nil if the file is not signed.
+func (file *peFile) authenticodeSignature() (*base.SignedData, error) {
+	offset, size := file.dataDirectory(dataDirectorySecurity)
+	if size == 0 {
+		return nil, nil
+	}
+	if int(offset)+int(size) > len(file.data) || size < winCertificateHeaderSize {
+		return nil, errors.New("the security directory exceeds the file size")
+	}
+	certificate := file.data[offset : offset+size]
+	length := binary.LittleEndian.Uint32(certificate[0:4])
+	if length > size || length < winCertificateHeaderSize {
+		return nil, errors.New("invalid certificate table")
+	}
+	if certificateType := binary.LittleEndian.Uint16(certificate[6:8]); certificateType != winCertificateTypePKCS7 {
+		return nil, fmt.Errorf("unsupported certificate type 0x%04X", certificateType)
+	}
+	return base.ParseSignedData(certificate[winCertificateHeaderSize:length])
+}
+
+// verifyAuthenticode checks that the signature covers the current content of the file.
+func (file *peFile) verifyAuthenticode(signedData *base.SignedData) error {
+	if err := signedData.VerifySignature(); err != nil {
+		return err
+	}
+	// Parse the content restoring the SEQUENCE tag omitted from the digest.
+	content, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: signedData.Content})
+	if err != nil {
+		return err
+	}
+	var indirectData spcIndirectDataContent
+	if _, err := asn1.Unmarshal(content, &indirectData); err != nil {
+		return fmt.Errorf("invalid Authenticode content: %w", err)
+	}
+	hash, err := signedData.DigestAlgorithm()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(file.authenticodeDigest(hash), indirectData.MessageDigest.Digest) {
+		return errors.New("the file has been modified after signing")
+	}
+	return nil
+}
+
+// authenticodeDigest computes the hash of the file as defined by Authenticode:
+// the whole file except for the checksum, the security directory entry,
+// and the certificate table.
+func (file *peFile) authenticodeDigest(hash crypto.Hash) []byte {
+	checksumOffset := file.optionalHeaderOffset + checkSumField
+	securityEntryOffset := file.dataDirectoryOffset + dataDirectorySecurity*dataDirectoryEntrySize
+	certificatesOffset, certificatesSize := file.dataDirectory(dataDirectorySecurity)
+
+	h := hash.New()
+	h.Write(file.data[:checksumOffset])
+	h.Write(file.data[checksumOffset+4 : securityEntryOffset])
+	h.Write(file.data[securityEntryOffset+dataDirectoryEntrySize : certificatesOffset])
+	h.Write(file.data[certificatesOffset+certificatesSize:])
+	return h.Sum(nil)
+}
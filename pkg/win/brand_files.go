@@ -0,0 +1,214 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// chromiumCompanyName is the CompanyName of the binaries built from
+// the Chromium sources.
+const chromiumCompanyName = "The Chromium Authors"
+
+// defaultBrandFiles is used if params.Win.BrandFiles is not set. Of these
+// files, only the ones whose CompanyName is chromiumCompanyName are stamped,
+// so that the third-party and vendor-signed binaries stay intact.
+var defaultBrandFiles = []common.BrandFile{
+	{Glob: "*.exe"},
+	{Glob: "*.dll"},
+}
+
+// brandFileStamp holds the version information to set in a binary.
+type brandFileStamp struct {
+	author         *string
+	productName    *string
+	legalCopyright *string
+	version        *common.Version
+	versionStrings map[string]string
+}
+
+// brandFileMatch is a binary selected by params.Win.BrandFiles.
+type brandFileMatch struct {
+	file  base.File
+	stamp brandFileStamp
+
+	// hasVersionInfo is set if the file is a PE file with the version information.
+	hasVersionInfo bool
+}
+
+// resolveBrandFiles returns the binaries in binariesDir selected by
+// params.Win.BrandFiles, except for the skipped ones. The overrides of all
+// the matching globs apply to a binary in the order of the globs.
+//
+// If the parameter is not set, the Chromium binaries among defaultBrandFiles
+// are returned.
+func resolveBrandFiles(params *common.BrandingParams, binariesDir base.Directory, skipped []base.AbsPath) ([]brandFileMatch, error) {
+	brandFiles := params.Win.BrandFiles
+	onlyChromium := brandFiles == nil
+	if onlyChromium {
+		brandFiles = defaultBrandFiles
+	}
+	for _, brandFile := range brandFiles {
+		glob := strings.TrimPrefix(brandFile.Glob, "!")
+		if glob == "" {
+			return nil, errors.New("win.brandFiles contains an empty glob")
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s in win.brandFiles: %w", brandFile.Glob, err)
+		}
+		if brandFile.Version != nil {
+			if err := brandFile.Version.ValidateForWin(); err != nil {
+				return nil, fmt.Errorf("%s: %w", brandFile.Glob, err)
+			}
+		}
+	}
+
+	matches := []brandFileMatch{}
+	root := binariesDir.AbsPath().String()
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		for _, skippedPath := range skipped {
+			if strings.EqualFold(filepath.Clean(filePath), skippedPath.String()) {
+				return nil
+			}
+		}
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		stamp, included := stampForFile(params, brandFiles, filepath.ToSlash(relPath))
+		if !included {
+			return nil
+		}
+		companyName, hasVersionInfo := readVersionString(filePath, authorVersionString)
+		if onlyChromium && companyName != chromiumCompanyName {
+			base.Logf("Skipping %s: its CompanyName is %q", relPath, companyName)
+			return nil
+		}
+		file, err := base.FileFromPathString(filePath)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, brandFileMatch{file: file, stamp: stamp, hasVersionInfo: hasVersionInfo})
+		return nil
+	})
+	return matches, err
+}
+
+// stampForFile applies the brand files to the path relative to the binaries
+// directory. Returns whether the last matching glob includes the file.
+func stampForFile(params *common.BrandingParams, brandFiles []common.BrandFile, relPath string) (brandFileStamp, bool) {
+	stamp := brandFileStamp{
+		author:         params.Win.Author,
+		productName:    params.Win.ProductName,
+		legalCopyright: params.Win.LegalCopyright,
+		version:        params.Version,
+		versionStrings: map[string]string{},
+	}
+	included := false
+	for _, brandFile := range brandFiles {
+		glob := brandFile.Glob
+		excluded := strings.HasPrefix(glob, "!")
+		if !base.MatchGlob(strings.TrimPrefix(glob, "!"), relPath) {
+			continue
+		}
+		included = !excluded
+		if excluded {
+			continue
+		}
+		if brandFile.Author != nil {
+			stamp.author = brandFile.Author
+		}
+		if brandFile.ProductName != nil {
+			stamp.productName = brandFile.ProductName
+		}
+		if brandFile.LegalCopyright != nil {
+			stamp.legalCopyright = brandFile.LegalCopyright
+		}
+		if brandFile.Version != nil {
+			stamp.version = brandFile.Version
+		}
+		for key, value := range brandFile.VersionStrings {
+			stamp.versionStrings[canonicalVersionStringKey(key)] = value
+		}
+	}
+	return stamp, included
+}
+
+// binariesToCheck returns the paths to the Chromium binaries and the PE files
+// among the brand files without duplicates.
+func binariesToCheck(binaries *ChromiumBinaries, brandFiles []brandFileMatch) []base.AbsPath {
+	paths := binaries.List()
+	for _, brandFile := range brandFiles {
+		path := brandFile.file.AbsPath()
+		if brandFile.hasVersionInfo && !base.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// editPlan plans setting the version information of the binary and removes
+// its signature. Returns nil if the binary has no version information or
+// the stamp sets nothing, in which case the binary stays signed.
+func (match brandFileMatch) editPlan() (*peEditPlan, error) {
+	if !match.hasVersionInfo {
+		fmt.Printf("WARNING: %s has no version information, skipping it.\n", match.file.AbsPath().String())
+		return nil, nil
+	}
+
+	plan := newPEEditPlan(UnsignedBinary{})
+	stamp := match.stamp
+	if stamp.author != nil {
+		plan.setVersionString(authorVersionString, *stamp.author)
+	}
+	if stamp.productName != nil {
+		plan.setVersionString(productNameVersionString, *stamp.productName)
+	}
+	if stamp.legalCopyright != nil {
+		plan.setVersionString(copyrightVersionString, *stamp.legalCopyright)
+	}
+	if stamp.version != nil {
+		plan.setVersion(stamp.version.FileVersion(), stamp.version.MarketingVersion())
+	}
+	plan.setVersionStrings(stamp.versionStrings)
+	if plan.isEmpty() {
+		return nil, nil
+	}
+
+	binaryFile, err := RemoveSignature(match.file)
+	if err != nil {
+		return nil, err
+	}
+	plan.binary = binaryFile
+	return plan, nil
+}
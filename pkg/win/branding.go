@@ -32,21 +32,12 @@ import (
 const originalChromiumExeName = "chromium"
 
 // WinBranding implements platform-specific branding logic for Windows.
-type WinBranding struct {
-	rceditTool *Rcedit
-}
+type WinBranding struct{}
 
-// GetPlatformBranding initializes a new WinBranding instance by
-// fetching the rcedit tool from a known URL, extracting it, and
-// storing its location in a WinBranding struct.
-//
-// Returns an error if the tool cannot be downloaded or extracted.
+// GetPlatformBranding returns a new WinBranding instance. The resources
+// of the binaries are edited in Go, so no external tools are needed.
 func GetPlatformBranding() (*WinBranding, error) {
-	if rceditTool, err := FetchRcedit(); err != nil {
-		return nil, err
-	} else {
-		return &WinBranding{rceditTool: rceditTool}, nil
-	}
+	return &WinBranding{}, nil
 }
 
 func (branding *WinBranding) ExecutableNameFile(params *common.BrandingParams, binariesDir base.Directory) (common.ExecutableNameFile, error) {
@@ -65,17 +56,32 @@ func (branding *WinBranding) ExecutableName(params *common.BrandingParams) strin
 	return originalChromiumExeName
 }
 
-// SetIcon calls the underlying rcedit tool to replace the icon
-// resource in the specified Windows executable or DLL file.
+// SetIcon replaces all the icon groups in the specified Windows
+// executable or DLL file with the given .ico file.
 func (branding *WinBranding) SetIcon(binaryFile UnsignedBinary, icon base.File) error {
-	return branding.rceditTool.SetIcon(binaryFile, icon)
+	data, err := icon.Read()
+	if err != nil {
+		return err
+	}
+	sorted, err := sortICO(data, true)
+	if err != nil {
+		return err
+	}
+	plan := newPEEditPlan(binaryFile)
+	plan.icons = iconAssignment{defaultIcon: sorted}
+	_, err = plan.apply()
+	return err
 }
 
 // SetFileDescription updates the FileDescription resource of the
 // specified Windows executable or DLL to the provided description.
 // This is often displayed in Task Manager or file properties.
 func (branding *WinBranding) SetFileDescription(description string, binaryFile UnsignedBinary) {
-	branding.rceditTool.SetProcessDescription(binaryFile, description)
+	plan := newPEEditPlan(binaryFile)
+	plan.setVersionString(fileDescriptionVersionString, description)
+	if _, err := plan.apply(); err != nil {
+		fmt.Printf("WARNING: cannot set the description of %s: %s\n", binaryFile.AbsPath().String(), err)
+	}
 }
 
 func (branding *WinBranding) CheckBinariesExist(binariesDir base.Directory) error {
@@ -86,11 +92,36 @@ func (branding *WinBranding) CheckBinariesExist(binariesDir base.Directory) erro
 }
 
 func (branding *WinBranding) Apply(params *common.BrandingParams, binariesDir base.Directory) error {
+	if params.Version != nil {
+		if err := params.Version.ValidateForWin(); err != nil {
+			return err
+		}
+	}
+
+	localizedVersionStrings, err := parseLocalizedVersionStrings(params)
+	if err != nil {
+		return err
+	}
+
+	icons, err := resolveIcons(params)
+	if err != nil {
+		return err
+	}
+
 	binariesToBrand, err := getChromiumBinaries(binariesDir)
 	if err != nil {
 		return err
 	}
 
+	brandFiles, err := resolveBrandFiles(params, binariesDir, []base.AbsPath{binariesToBrand.ChromiumExePath()})
+	if err != nil {
+		return err
+	}
+
+	if err := checkArchitecture(binariesToCheck(binariesToBrand, brandFiles)); err != nil {
+		return err
+	}
+
 	initialChromiumExecutable, err := binariesToBrand.ChromiumExePath().AsFile()
 	if err != nil {
 		return err
@@ -110,57 +141,65 @@ func (branding *WinBranding) Apply(params *common.BrandingParams, binariesDir ba
 		}
 	}
 
+	executablePlan := newPEEditPlan(chromiumExecutable)
 	if params.Win.Author != nil {
-		if err := branding.rceditTool.SetAuthor(chromiumExecutable, *params.Win.Author); err != nil {
-			return err
-		}
+		executablePlan.setVersionString(authorVersionString, *params.Win.Author)
 	}
-
 	if params.Win.ProductName != nil {
-		if err := branding.rceditTool.SetProductName(chromiumExecutable, *params.Win.ProductName); err != nil {
-			return err
-		}
+		executablePlan.setVersionString(productNameVersionString, *params.Win.ProductName)
 	}
-
 	if params.Version != nil {
-		if err := branding.rceditTool.SetVersion(chromiumExecutable, *params.Version); err != nil {
-			return err
-		}
+		executablePlan.setVersion(params.Version.FileVersion(), params.Version.MarketingVersion())
 	}
-
 	if params.Win.ProcessDisplayName != nil {
-		if err := branding.rceditTool.SetProcessDescription(chromiumExecutable, *params.Win.ProcessDisplayName); err != nil {
-			return err
-		}
+		executablePlan.setVersionString(fileDescriptionVersionString, *params.Win.ProcessDisplayName)
 	}
-
 	if params.Win.LegalCopyright != nil {
-		if err := branding.rceditTool.SetCopyright(chromiumExecutable, *params.Win.LegalCopyright); err != nil {
-			return err
-		}
+		executablePlan.setVersionString(copyrightVersionString, *params.Win.LegalCopyright)
 	}
-
-	if params.Win.IcoPath != nil {
-		icon, err := base.FileFromPathString(*params.Win.IcoPath)
+	executablePlan.setVersionStrings(versionStringsToSet(params))
+	executablePlan.localizedVersionStrings = localizedVersionStrings
+	executablePlan.manifest = params.Win.Manifest
+	executablePlan.icons = icons
+	plans := []*peEditPlan{executablePlan}
+
+	for _, brandFile := range brandFiles {
+		plan, err := brandFile.editPlan()
 		if err != nil {
 			return err
 		}
+		if plan != nil {
+			plans = append(plans, plan)
+		}
+	}
 
-		initialChromeDll, err := binariesToBrand.ChromeDllPath().AsFile()
-		if err != nil {
-			return err
+	if !icons.isEmpty() {
+		chromeDllPlan := findEditPlan(plans, binariesToBrand.ChromeDllPath())
+		if chromeDllPlan == nil {
+			initialChromeDll, err := binariesToBrand.ChromeDllPath().AsFile()
+			if err != nil {
+				return err
+			}
+			chromeDll, err := RemoveSignature(initialChromeDll)
+			if err != nil {
+				return err
+			}
+			chromeDllPlan = newPEEditPlan(chromeDll)
+			plans = append(plans, chromeDllPlan)
 		}
+		chromeDllPlan.icons = icons
+	}
 
-		chromeDll, err := RemoveSignature(initialChromeDll)
+	foundGroupIDs := []resourceID{}
+	for _, plan := range plans {
+		groupIDs, err := plan.apply()
 		if err != nil {
 			return err
 		}
-
-		for _, binaryFile := range []UnsignedBinary{chromiumExecutable, chromeDll} {
-			if err := branding.SetIcon(binaryFile, icon); err != nil {
-				return err
-			}
-		}
+		foundGroupIDs = append(foundGroupIDs, groupIDs...)
+	}
+	if !icons.isEmpty() {
+		warnAboutUnusedIconGroups(icons, foundGroupIDs)
 	}
 
 	return removeSigFiles(binariesDir)
@@ -0,0 +1,186 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// peEditPlan collects the resource changes of a binary, so that they are
+// applied in a single pass: the file is read and written once, and its
+// checksum is recomputed once.
+type peEditPlan struct {
+	binary UnsignedBinary
+
+	// fileVersion and productVersion are empty if the versions stay intact.
+	fileVersion    string
+	productVersion string
+
+	// versionStrings are set in every string table of the version information.
+	versionStrings map[string]string
+
+	// localizedVersionStrings maps the language identifiers to the strings
+	// of the string tables of these languages.
+	localizedVersionStrings map[uint16]map[string]string
+
+	icons    iconAssignment
+	manifest *common.Manifest
+}
+
+// newPEEditPlan creates an empty edit plan for the binary.
+func newPEEditPlan(binary UnsignedBinary) *peEditPlan {
+	return &peEditPlan{binary: binary, versionStrings: map[string]string{}}
+}
+
+// setVersion plans setting the file and the product versions, both the
+// binary ones and the FileVersion and ProductVersion strings.
+func (plan *peEditPlan) setVersion(fileVersion, productVersion string) {
+	plan.fileVersion = fileVersion
+	plan.productVersion = productVersion
+	plan.versionStrings[fileVersionVersionString] = fileVersion
+	plan.versionStrings[productVersionVersionString] = productVersion
+}
+
+// setVersionString plans setting the StringFileInfo value. A later value
+// for the same key replaces the earlier one.
+func (plan *peEditPlan) setVersionString(key, value string) {
+	plan.versionStrings[key] = value
+}
+
+// setVersionStrings plans setting all the StringFileInfo values.
+func (plan *peEditPlan) setVersionStrings(versionStrings map[string]string) {
+	for key, value := range versionStrings {
+		plan.setVersionString(key, value)
+	}
+}
+
+// changesVersionInfo reports whether the plan modifies the version information.
+func (plan *peEditPlan) changesVersionInfo() bool {
+	return plan.fileVersion != "" || plan.productVersion != "" ||
+		len(plan.versionStrings) != 0 || len(plan.localizedVersionStrings) != 0
+}
+
+// isEmpty reports whether the plan changes nothing.
+func (plan *peEditPlan) isEmpty() bool {
+	return !plan.changesVersionInfo() && plan.icons.isEmpty() && plan.manifest == nil
+}
+
+// apply makes all the planned changes and rewrites the binary.
+// Returns the ids of the icon groups found in the binary if the plan
+// replaces the icons.
+func (plan *peEditPlan) apply() ([]resourceID, error) {
+	if plan.isEmpty() {
+		return nil, nil
+	}
+	binaryPath := plan.binary.AbsPath()
+	pe, err := readPEFile(binaryPath.String())
+	if err != nil {
+		return nil, err
+	}
+	resources, err := pe.readResources()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", binaryPath.String(), err)
+	}
+
+	var groupIDs []resourceID
+	if !plan.icons.isEmpty() {
+		if groupIDs, err = resources.replaceIconGroups(binaryPath, plan.icons); err != nil {
+			return nil, fmt.Errorf("%s: %w", binaryPath.String(), err)
+		}
+	}
+	if plan.changesVersionInfo() {
+		if err := plan.applyVersionInfo(resources); err != nil {
+			return nil, fmt.Errorf("%s: %w", binaryPath.String(), err)
+		}
+	}
+	if plan.manifest != nil {
+		if err := resources.replaceManifest(binaryPath, plan.manifest); err != nil {
+			return nil, fmt.Errorf("%s: %w", binaryPath.String(), err)
+		}
+	}
+
+	if err := pe.writeResources(resources); err != nil {
+		return nil, fmt.Errorf("%s: %w", binaryPath.String(), err)
+	}
+	return groupIDs, pe.save()
+}
+
+// applyVersionInfo makes the planned changes in every language variant of
+// the version information.
+func (plan *peEditPlan) applyVersionInfo(resources *peResources) error {
+	versions := resources.ofType(intResourceID(resourceTypeVersion))
+	if len(versions) == 0 {
+		return errors.New("the file has no version information")
+	}
+
+	keys := []string{}
+	for key := range plan.versionStrings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Println("Setting " + key + " for " + plan.binary.AbsPath().String() + " : " + plan.versionStrings[key])
+	}
+
+	for _, version := range versions {
+		root, err := parseVersionInfo(version.data)
+		if err != nil {
+			return err
+		}
+		if plan.fileVersion != "" {
+			if err := root.setFixedVersion(fixedFileVersionOffset, plan.fileVersion); err != nil {
+				return err
+			}
+		}
+		if plan.productVersion != "" {
+			if err := root.setFixedVersion(fixedProductVersionOffset, plan.productVersion); err != nil {
+				return err
+			}
+		}
+		if len(plan.versionStrings) != 0 {
+			if err := setVersionStrings(root, plan.versionStrings); err != nil {
+				return err
+			}
+		}
+		if len(plan.localizedVersionStrings) != 0 {
+			if err := localizeVersionInfo(root, plan.localizedVersionStrings); err != nil {
+				return err
+			}
+		}
+		version.data = root.serialize()
+	}
+	return nil
+}
+
+// findEditPlan returns the plan for the binary at the path, or nil.
+func findEditPlan(plans []*peEditPlan, path base.AbsPath) *peEditPlan {
+	for _, plan := range plans {
+		if plan.binary.AbsPath() == path {
+			return plan
+		}
+	}
+	return nil
+}
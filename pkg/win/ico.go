@@ -21,17 +21,38 @@
 package win
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"sort"
 )
 
 const (
 	iconDirSize   = 6
 	iconEntrySize = 16
+
+	bitmapInfoHeaderSize = 40
+	icoMaxImageSize      = 256
+	icoPNGMinImageSize   = 256
+	icoBitCount          = 32
 )
 
+// pngSignature starts the images stored in the PNG format.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// recommendedIconSizes lists the image sizes Windows picks for the
+// taskbar, Explorer views, and the Alt+Tab switcher at common DPI settings.
+var recommendedIconSizes = []int{16, 24, 32, 48, 256}
+
+// icoIconSizes lists the image sizes generated from the master icon, from the
+// largest to the smallest. It adds the sizes used at 125% and 250% DPI scaling
+// to recommendedIconSizes.
+var icoIconSizes = []int{256, 64, 48, 40, 32, 24, 20, 16}
+
 type IconEntry struct {
 	WidthByte  byte
 	HeightByte byte
@@ -61,7 +82,9 @@ func (e IconEntry) Height() int {
 	return int(e.HeightByte)
 }
 
-func sortICO(data []byte, descending bool) ([]byte, error) {
+// parseICO reads the ICONDIR header and the ICONDIRENTRY records of the ICO
+// file and returns the entries with their image data.
+func parseICO(data []byte) ([]IconEntry, error) {
 	if len(data) < iconDirSize {
 		return nil, errors.New("file too small for ICO header")
 	}
@@ -120,6 +143,16 @@ func sortICO(data []byte, descending bool) ([]byte, error) {
 		entries = append(entries, e)
 	}
 
+	return entries, nil
+}
+
+func sortICO(data []byte, descending bool) ([]byte, error) {
+	entries, err := parseICO(data)
+	if err != nil {
+		return nil, err
+	}
+	count := len(entries)
+
 	sort.SliceStable(entries, func(i, j int) bool {
 		a := entries[i]
 		b := entries[j]
@@ -159,10 +192,10 @@ func sortICO(data []byte, descending bool) ([]byte, error) {
 
 	// Reserve space for sorted ICONDIRENTRY records.
 	entryStart := len(out)
-	out = append(out, make([]byte, int(count)*iconEntrySize)...)
+	out = append(out, make([]byte, count*iconEntrySize)...)
 
 	// Write image data in the same order as the sorted directory.
-	currentOffset := iconDirSize + int(count)*iconEntrySize
+	currentOffset := iconDirSize + count*iconEntrySize
 
 	for i := range entries {
 		entries[i].ImageOffset = uint32(currentOffset)
@@ -189,3 +222,137 @@ func sortICO(data []byte, descending bool) ([]byte, error) {
 
 	return out, nil
 }
+
+// checkICO reports the non-square images of the ICO data as errors, the same
+// way as for an iconset, and prints a warning for every recommended icon size
+// missing from it and for every bitmap image with less than 32 bits per pixel.
+func checkICO(data []byte) error {
+	entries, err := parseICO(data)
+	if err != nil {
+		return err
+	}
+
+	problems := []error{}
+	for i, e := range entries {
+		width, height := e.Width(), e.Height()
+		if bytes.HasPrefix(e.Data, pngSignature) {
+			config, err := png.DecodeConfig(bytes.NewReader(e.Data))
+			if err != nil {
+				problems = append(problems, fmt.Errorf("image #%d: %w", i+1, err))
+				continue
+			}
+			width, height = config.Width, config.Height
+		}
+		if width != height {
+			problems = append(problems, fmt.Errorf("image #%d: the image is not square (%dx%d pixels)", i+1, width, height))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+
+	sizes := map[int]string{}
+	for _, e := range entries {
+		sizes[e.Width()] = ""
+		if !bytes.HasPrefix(e.Data, pngSignature) && e.BitCountOrHotY < icoBitCount {
+			fmt.Printf("WARNING: the %dx%d icon image has %d bits per pixel, 32-bit images are recommended.\n", e.Width(), e.Height(), e.BitCountOrHotY)
+		}
+	}
+	warnAboutMissingIconSizes(sizes)
+	return nil
+}
+
+// encodeICO writes the given square images into the ICO container in the
+// given order. Images of 256 pixels are stored as PNG, smaller ones as 32-bit
+// bitmaps (DIB) with an AND mask, which every Windows version can render.
+func encodeICO(images []image.Image) ([]byte, error) {
+	entries := make([]IconEntry, 0, len(images))
+	for _, img := range images {
+		size := img.Bounds().Dx()
+		if size != img.Bounds().Dy() || size == 0 || size > icoMaxImageSize {
+			return nil, fmt.Errorf("unsupported icon image size %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+		}
+
+		var data []byte
+		if size >= icoPNGMinImageSize {
+			var buffer bytes.Buffer
+			if err := png.Encode(&buffer, img); err != nil {
+				return nil, err
+			}
+			data = buffer.Bytes()
+		} else {
+			data = encodeDIB(img)
+		}
+
+		entries = append(entries, IconEntry{
+			// The width and height of 256 pixels are stored as 0.
+			WidthByte:        byte(size % 256),
+			HeightByte:       byte(size % 256),
+			PlanesOrHotspotX: 1,
+			BitCountOrHotY:   icoBitCount,
+			BytesInRes:       uint32(len(data)),
+			Data:             data,
+		})
+	}
+
+	out := make([]byte, iconDirSize+len(entries)*iconEntrySize)
+	binary.LittleEndian.PutUint16(out[2:4], 1)
+	binary.LittleEndian.PutUint16(out[4:6], uint16(len(entries)))
+
+	currentOffset := iconDirSize + len(entries)*iconEntrySize
+	for i, e := range entries {
+		p := iconDirSize + i*iconEntrySize
+
+		out[p+0] = e.WidthByte
+		out[p+1] = e.HeightByte
+		binary.LittleEndian.PutUint16(out[p+4:p+6], e.PlanesOrHotspotX)
+		binary.LittleEndian.PutUint16(out[p+6:p+8], e.BitCountOrHotY)
+		binary.LittleEndian.PutUint32(out[p+8:p+12], e.BytesInRes)
+		binary.LittleEndian.PutUint32(out[p+12:p+16], uint32(currentOffset))
+
+		currentOffset += len(e.Data)
+	}
+	for _, e := range entries {
+		out = append(out, e.Data...)
+	}
+
+	return out, nil
+}
+
+// encodeDIB encodes the image as a 32-bit BGRA bitmap preceded by
+// BITMAPINFOHEADER and followed by the 1-bit AND mask, as stored in ICO files.
+// The rows are stored bottom-up, and the header height covers both the color
+// bitmap and the mask.
+func encodeDIB(img image.Image) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	maskStride := ((width + 31) / 32) * 4
+	colorSize := width * height * 4
+	maskSize := maskStride * height
+
+	out := make([]byte, bitmapInfoHeaderSize+colorSize+maskSize)
+	binary.LittleEndian.PutUint32(out[0:4], bitmapInfoHeaderSize)
+	binary.LittleEndian.PutUint32(out[4:8], uint32(width))
+	binary.LittleEndian.PutUint32(out[8:12], uint32(height*2))
+	binary.LittleEndian.PutUint16(out[12:14], 1)
+	binary.LittleEndian.PutUint16(out[14:16], icoBitCount)
+	binary.LittleEndian.PutUint32(out[20:24], uint32(colorSize+maskSize))
+
+	colors := out[bitmapInfoHeaderSize : bitmapInfoHeaderSize+colorSize]
+	mask := out[bitmapInfoHeaderSize+colorSize:]
+	for y := 0; y < height; y++ {
+		row := height - 1 - y
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			p := (row*width + x) * 4
+			colors[p+0] = c.B
+			colors[p+1] = c.G
+			colors[p+2] = c.R
+			colors[p+3] = c.A
+			if c.A == 0 {
+				mask[row*maskStride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestCheckICORejectsNonSquareImages(t *testing.T) {
+	ico, err := encodeICO([]image.Image{
+		image.NewNRGBA(image.Rect(0, 0, 16, 16)),
+		image.NewNRGBA(image.Rect(0, 0, 32, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkICO(ico); err != nil {
+		t.Fatalf("square images are reported: %v", err)
+	}
+
+	// Make the directory entry of the second image 32x48.
+	ico[iconDirSize+iconEntrySize+1] = 48
+	err = checkICO(ico)
+	if err == nil || !strings.Contains(err.Error(), "not square (32x48 pixels)") {
+		t.Fatalf("expected a non-square image error, got %v", err)
+	}
+}
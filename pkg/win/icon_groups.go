@@ -0,0 +1,189 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// defaultIconGroupKey is the key of params.Win.Icons that sets the icon
+// for all the icon groups not listed explicitly.
+const defaultIconGroupKey = "default"
+
+// iconAssignment holds the ICO data to embed into the icon groups of the binaries.
+type iconAssignment struct {
+	// defaultIcon replaces every icon group missing from groupIcons. May be nil.
+	defaultIcon []byte
+
+	// groupIcons maps the icon group ids (as printed by resourceID.String)
+	// to the ICO data.
+	groupIcons map[string][]byte
+}
+
+// isEmpty reports whether no icon group is to be replaced.
+func (icons iconAssignment) isEmpty() bool {
+	return icons.defaultIcon == nil && len(icons.groupIcons) == 0
+}
+
+// iconFor returns the ICO data for the given icon group, or nil if the group
+// should be left intact.
+func (icons iconAssignment) iconFor(groupID resourceID) []byte {
+	for key, ico := range icons.groupIcons {
+		if strings.EqualFold(key, groupID.String()) {
+			return ico
+		}
+	}
+	return icons.defaultIcon
+}
+
+// resolveIcons reads the .ico files for the icon groups from params.Win.Icons.
+// The default icon is either params.Win.Icons["default"] or the icon resolved
+// by resolveIcon. All the icons are sorted largest-to-smallest.
+// See https://github.com/TeamDev-IP/Chromium-Branding/issues/25.
+func resolveIcons(params *common.BrandingParams) (iconAssignment, error) {
+	icons := iconAssignment{groupIcons: map[string][]byte{}}
+
+	defaultIcon, err := resolveIcon(params)
+	if err != nil {
+		return iconAssignment{}, err
+	}
+	icons.defaultIcon = defaultIcon
+
+	for key, path := range params.Win.Icons {
+		ico, err := readICOFile(path)
+		if err != nil {
+			return iconAssignment{}, err
+		}
+		if strings.EqualFold(key, defaultIconGroupKey) {
+			if defaultIcon != nil {
+				fmt.Println("Using " + path + " for all the icon groups instead of the app icon")
+			}
+			icons.defaultIcon = ico
+			continue
+		}
+		icons.groupIcons[key] = ico
+	}
+
+	if icons.defaultIcon != nil {
+		if icons.defaultIcon, err = sortICO(icons.defaultIcon, true); err != nil {
+			return iconAssignment{}, err
+		}
+	}
+	for key, ico := range icons.groupIcons {
+		if icons.groupIcons[key], err = sortICO(ico, true); err != nil {
+			return iconAssignment{}, err
+		}
+	}
+	return icons, nil
+}
+
+// resolveIcon returns the ICO data of the app icon. The explicit params.Win.IcoPath
+// takes precedence over params.Win.IconsetPath and then over params.Icon,
+// from which the ICO data is built. Returns nil if no icon is configured.
+func resolveIcon(params *common.BrandingParams) ([]byte, error) {
+	if params.Win.IcoPath != nil {
+		if params.Win.IconsetPath != nil {
+			fmt.Println("Both win.icoPath and win.iconsetPath are set, using " + *params.Win.IcoPath)
+		}
+		return readICOFile(*params.Win.IcoPath)
+	}
+
+	if params.Win.IconsetPath != nil {
+		fmt.Println("Building the .ico file from " + *params.Win.IconsetPath)
+		iconsetDir, err := base.DirectoryFromPathString(*params.Win.IconsetPath)
+		if err != nil {
+			return nil, err
+		}
+		return buildICOFromIconset(iconsetDir)
+	}
+
+	if params.Icon != nil {
+		fmt.Println("Generating the .ico file from " + params.Icon.Path)
+		return buildICOFromIcon(params.Icon)
+	}
+
+	return nil, nil
+}
+
+// readICOFile reads the .ico file and warns about its missing recommended sizes.
+func readICOFile(path string) ([]byte, error) {
+	icon, err := base.FileFromPathString(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := icon.Read()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkICO(data); err != nil {
+		return nil, fmt.Errorf("invalid icon %s: %w", icon.AbsPath().String(), err)
+	}
+	return data, nil
+}
+
+// replaceIconGroups replaces the icon groups of the binary according to
+// the assignment. Returns the ids of all the icon groups found in the binary.
+func (resources *peResources) replaceIconGroups(binaryPath base.AbsPath, icons iconAssignment) ([]resourceID, error) {
+	groupIDs := resources.iconGroupIDs()
+	groupNames := []string{}
+	for _, groupID := range groupIDs {
+		groupNames = append(groupNames, groupID.String())
+	}
+	fmt.Println("Icon groups in " + binaryPath.Base() + ": " + strings.Join(groupNames, ", "))
+
+	for _, groupID := range groupIDs {
+		ico := icons.iconFor(groupID)
+		if ico == nil {
+			continue
+		}
+		fmt.Println("Setting icon group " + groupID.String() + " for " + binaryPath.String())
+		if err := resources.replaceIconGroup(groupID, ico); err != nil {
+			return nil, err
+		}
+	}
+	return groupIDs, nil
+}
+
+// warnAboutUnusedIconGroups prints a warning for every icon group from
+// params.Win.Icons that has not been found in any of the binaries.
+func warnAboutUnusedIconGroups(icons iconAssignment, foundGroupIDs []resourceID) {
+	unused := []string{}
+	for key := range icons.groupIcons {
+		found := false
+		for _, groupID := range foundGroupIDs {
+			if strings.EqualFold(key, groupID.String()) {
+				found = true
+			}
+		}
+		if !found {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	for _, key := range unused {
+		fmt.Printf("WARNING: icon group %s from win.icons has not been found in the binaries.\n", key)
+	}
+}
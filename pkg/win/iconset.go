@@ -0,0 +1,171 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	pngColorTypeGray      = 0
+	pngColorTypeTrueColor = 2
+	pngColorTypePalette   = 3
+	pngBitDepthOffset     = 24
+	pngColorTypeOffset    = 25
+)
+
+// iconsetFileNamePattern matches the iconset file names such as "Icon_16x16.png".
+var iconsetFileNamePattern = regexp.MustCompile(`^icon_(\d+)x(\d+)\.png$`)
+
+// iconsetImage is a single decoded image of the iconset.
+type iconsetImage struct {
+	fileName string
+	image    image.Image
+}
+
+// buildICOFromIconset reads the PNG files named Icon_<size>x<size>.png from
+// the iconsetDir directory and encodes them into the ICO container.
+//
+// Non-square images, images whose pixel dimensions differ from the file name,
+// and images larger than 256 pixels are reported as errors. Missing recommended
+// sizes and images that are not 8-bit RGBA are reported as warnings.
+func buildICOFromIconset(iconsetDir base.Directory) ([]byte, error) {
+	images := []iconsetImage{}
+	problems := []error{}
+
+	for _, file := range iconsetDir.ListFiles() {
+		fileName := file.AbsPath().Base()
+		if !strings.EqualFold(filepath.Ext(fileName), ".png") {
+			continue
+		}
+
+		match := iconsetFileNamePattern.FindStringSubmatch(strings.ToLower(fileName))
+		if match == nil {
+			fmt.Printf("WARNING: %s is ignored, expected Icon_<size>x<size>.png.\n", fileName)
+			continue
+		}
+		width, _ := strconv.Atoi(match[1])
+		height, _ := strconv.Atoi(match[2])
+
+		data, err := file.Read()
+		if err != nil {
+			return nil, err
+		}
+		img, err := decodeIconsetImage(fileName, data, width, height)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		images = append(images, iconsetImage{fileName: fileName, image: img})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid iconset %s:\n%w", iconsetDir.AbsPath().String(), errors.Join(problems...))
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no Icon_<size>x<size>.png images found in %s", iconsetDir.AbsPath().String())
+	}
+
+	sizes := map[int]string{}
+	for _, img := range images {
+		size := img.image.Bounds().Dx()
+		if other, exists := sizes[size]; exists {
+			return nil, fmt.Errorf("%s and %s have the same size %dx%d", other, img.fileName, size, size)
+		}
+		sizes[size] = img.fileName
+	}
+	warnAboutMissingIconSizes(sizes)
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].image.Bounds().Dx() > images[j].image.Bounds().Dx()
+	})
+	decoded := make([]image.Image, 0, len(images))
+	for _, img := range images {
+		decoded = append(decoded, img.image)
+	}
+	return encodeICO(decoded)
+}
+
+// buildICOFromIcon generates the images of icoIconSizes from the master icon
+// and encodes them into the ICO container.
+func buildICOFromIcon(icon *common.Icon) ([]byte, error) {
+	images := make([]image.Image, 0, len(icoIconSizes))
+	for _, size := range icoIconSizes {
+		img, err := icon.Image(size)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return encodeICO(images)
+}
+
+// decodeIconsetImage decodes the PNG data of the iconset file and checks
+// its dimensions against the width and height from the file name.
+func decodeIconsetImage(fileName string, data []byte, width, height int) (image.Image, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: not a valid PNG image: %w", fileName, err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() != bounds.Dy() {
+		return nil, fmt.Errorf("%s: the image is not square (%dx%d pixels)", fileName, bounds.Dx(), bounds.Dy())
+	}
+	if bounds.Dx() != width || bounds.Dy() != height {
+		return nil, fmt.Errorf("%s: expected %dx%d pixels, got %dx%d", fileName, width, height, bounds.Dx(), bounds.Dy())
+	}
+	if bounds.Dx() > icoMaxImageSize {
+		return nil, fmt.Errorf("%s: the image exceeds the maximum ICO size of %dx%d pixels", fileName, icoMaxImageSize, icoMaxImageSize)
+	}
+
+	bitDepth, colorType := data[pngBitDepthOffset], data[pngColorTypeOffset]
+	switch {
+	case colorType == pngColorTypeGray || colorType == pngColorTypeTrueColor || colorType == pngColorTypePalette:
+		fmt.Printf("WARNING: %s is not an RGBA image and will be converted to 32-bit.\n", fileName)
+	case bitDepth != 8:
+		fmt.Printf("WARNING: %s has %d bits per channel and will be converted to 8 bits per channel.\n", fileName, bitDepth)
+	}
+
+	return img, nil
+}
+
+// warnAboutMissingIconSizes prints a warning for every recommended icon size
+// that is missing from sizes.
+func warnAboutMissingIconSizes(sizes map[int]string) {
+	for _, size := range recommendedIconSizes {
+		if _, ok := sizes[size]; !ok {
+			fmt.Printf("WARNING: the icon has no %dx%d image, Windows will scale another one which may look blurry.\n", size, size)
+		}
+	}
+}
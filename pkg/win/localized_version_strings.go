@@ -0,0 +1,219 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+// languageIDs maps the BCP-47 language tags to the Windows language
+// identifiers (LCIDs). A tag without a region maps to the primary region
+// of the language.
+var languageIDs = map[string]uint16{
+	"ar": 0x0401, "ar-sa": 0x0401, "ar-eg": 0x0C01, "ar-ae": 0x3801,
+	"bg": 0x0402, "bg-bg": 0x0402,
+	"ca": 0x0403, "ca-es": 0x0403,
+	"cs": 0x0405, "cs-cz": 0x0405,
+	"da": 0x0406, "da-dk": 0x0406,
+	"de": 0x0407, "de-de": 0x0407, "de-at": 0x0C07, "de-ch": 0x0807,
+	"el": 0x0408, "el-gr": 0x0408,
+	"en": 0x0409, "en-us": 0x0409, "en-gb": 0x0809, "en-au": 0x0C09, "en-ca": 0x1009,
+	"es": 0x0C0A, "es-es": 0x0C0A, "es-mx": 0x080A, "es-419": 0x580A,
+	"et": 0x0425, "et-ee": 0x0425,
+	"fa": 0x0429, "fa-ir": 0x0429,
+	"fi": 0x040B, "fi-fi": 0x040B,
+	"fr": 0x040C, "fr-fr": 0x040C, "fr-ca": 0x0C0C, "fr-be": 0x080C, "fr-ch": 0x100C,
+	"he": 0x040D, "he-il": 0x040D,
+	"hi": 0x0439, "hi-in": 0x0439,
+	"hr": 0x041A, "hr-hr": 0x041A,
+	"hu": 0x040E, "hu-hu": 0x040E,
+	"id": 0x0421, "id-id": 0x0421,
+	"it": 0x0410, "it-it": 0x0410, "it-ch": 0x0810,
+	"ja": 0x0411, "ja-jp": 0x0411,
+	"ko": 0x0412, "ko-kr": 0x0412,
+	"lt": 0x0427, "lt-lt": 0x0427,
+	"lv": 0x0426, "lv-lv": 0x0426,
+	"ms": 0x043E, "ms-my": 0x043E,
+	"nb": 0x0414, "nb-no": 0x0414, "no": 0x0414,
+	"nl": 0x0413, "nl-nl": 0x0413, "nl-be": 0x0813,
+	"pl": 0x0415, "pl-pl": 0x0415,
+	"pt": 0x0416, "pt-br": 0x0416, "pt-pt": 0x0816,
+	"ro": 0x0418, "ro-ro": 0x0418,
+	"ru": 0x0419, "ru-ru": 0x0419,
+	"sk": 0x041B, "sk-sk": 0x041B,
+	"sl": 0x0424, "sl-si": 0x0424,
+	"sr": 0x241A, "sr-latn-rs": 0x241A, "sr-cyrl-rs": 0x281A,
+	"sv": 0x041D, "sv-se": 0x041D,
+	"th": 0x041E, "th-th": 0x041E,
+	"tr": 0x041F, "tr-tr": 0x041F,
+	"uk": 0x0422, "uk-ua": 0x0422,
+	"vi": 0x042A, "vi-vn": 0x042A,
+	"zh": 0x0804, "zh-cn": 0x0804, "zh-hans": 0x0804, "zh-sg": 0x1004,
+	"zh-tw": 0x0404, "zh-hant": 0x0404, "zh-hk": 0x0C04,
+}
+
+// versionTranslation is a language and code page pair that identifies
+// a StringTable structure and is listed in VarFileInfo\Translation.
+type versionTranslation struct {
+	language uint16
+	codePage uint16
+}
+
+// key returns the key of the StringTable structure for the translation.
+func (translation versionTranslation) key() string {
+	return fmt.Sprintf("%04x%04x", translation.language, translation.codePage)
+}
+
+// parseLanguageID converts a decimal LCID (1041), a hexadecimal LCID (0x0411),
+// or a BCP-47 language tag (ja-JP) to the Windows language identifier.
+func parseLanguageID(value string) (uint16, error) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "_", "-"))
+	if strings.HasPrefix(tag, "0x") {
+		if id, err := strconv.ParseUint(tag[2:], 16, 16); err == nil && id != 0 {
+			return uint16(id), nil
+		}
+	} else if id, err := strconv.ParseUint(tag, 10, 16); err == nil && id != 0 {
+		return uint16(id), nil
+	} else if id, ok := languageIDs[tag]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown language %q, use an LCID such as 0x0411 or a language tag such as ja-JP", value)
+}
+
+// parseLocalizedVersionStrings converts the keys of params.Win.LocalizedVersionStrings
+// to the language identifiers.
+func parseLocalizedVersionStrings(params *common.BrandingParams) (map[uint16]map[string]string, error) {
+	localized := map[uint16]map[string]string{}
+	for language, versionStrings := range params.Win.LocalizedVersionStrings {
+		id, err := parseLanguageID(language)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := localized[id]; ok {
+			return nil, fmt.Errorf("the language %s is listed in win.localizedVersionStrings more than once", language)
+		}
+		localized[id] = map[string]string{}
+		for key, value := range versionStrings {
+			localized[id][canonicalVersionStringKey(key)] = value
+		}
+	}
+	return localized, nil
+}
+
+// localizeVersionInfo adds a StringTable structure for every language to
+// the version information and lists the languages in VarFileInfo\Translation.
+// A new table starts as a copy of the first existing one, so the strings
+// not translated keep their values.
+func localizeVersionInfo(root *versionInfoNode, localized map[uint16]map[string]string) error {
+	stringFileInfo := root.child(stringFileInfoKey)
+	if stringFileInfo == nil || len(stringFileInfo.children) == 0 {
+		return errors.New("the version information has no string table")
+	}
+	baseTable := stringFileInfo.children[0]
+
+	languages := []uint16{}
+	for language := range localized {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i] < languages[j] })
+
+	translations := versionTranslations(root)
+	for _, language := range languages {
+		translation := versionTranslation{language: language, codePage: unicodeCodePage}
+		table := stringFileInfo.child(translation.key())
+		if table == nil {
+			table = &versionInfoNode{key: translation.key(), isText: true}
+			for _, baseString := range baseTable.children {
+				table.children = append(table.children, newVersionString(baseString.key, baseString.text()))
+			}
+			stringFileInfo.children = append(stringFileInfo.children, table)
+		}
+
+		keys := []string{}
+		for key := range localized[language] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("Setting %s (%s) : %s\n", key, translation.key(), localized[language][key])
+			table.setString(key, localized[language][key])
+		}
+
+		listed := false
+		for _, existing := range translations {
+			listed = listed || existing == translation
+		}
+		if !listed {
+			translations = append(translations, translation)
+		}
+	}
+
+	setVersionTranslations(root, translations)
+	return nil
+}
+
+// versionTranslations returns the translations listed in VarFileInfo\Translation.
+func versionTranslations(root *versionInfoNode) []versionTranslation {
+	translations := []versionTranslation{}
+	varFileInfo := root.child(varFileInfoKey)
+	if varFileInfo == nil {
+		return translations
+	}
+	translation := varFileInfo.child(translationKey)
+	if translation == nil {
+		return translations
+	}
+	for i := 0; i+4 <= len(translation.value); i += 4 {
+		translations = append(translations, versionTranslation{
+			language: binary.LittleEndian.Uint16(translation.value[i:]),
+			codePage: binary.LittleEndian.Uint16(translation.value[i+2:]),
+		})
+	}
+	return translations
+}
+
+// setVersionTranslations replaces the translations listed in VarFileInfo\Translation,
+// adding the structures if necessary.
+func setVersionTranslations(root *versionInfoNode, translations []versionTranslation) {
+	varFileInfo := root.child(varFileInfoKey)
+	if varFileInfo == nil {
+		varFileInfo = &versionInfoNode{key: varFileInfoKey, isText: true}
+		root.children = append(root.children, varFileInfo)
+	}
+	translation := varFileInfo.child(translationKey)
+	if translation == nil {
+		translation = &versionInfoNode{key: translationKey}
+		varFileInfo.children = append(varFileInfo.children, translation)
+	}
+	translation.isText = false
+	translation.value = make([]byte, 4*len(translations))
+	for i, pair := range translations {
+		binary.LittleEndian.PutUint16(translation.value[4*i:], pair.language)
+		binary.LittleEndian.PutUint16(translation.value[4*i+2:], pair.codePage)
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	manifestResourceID = 1
+
+	// englishLanguageID is the language of the manifest added to a binary
+	// that has none, as the resource compiler does by default.
+	englishLanguageID = 0x0409
+
+	asmV3Namespace               = "urn:schemas-microsoft-com:asm.v3"
+	windowsSettings2005Namespace = "http://schemas.microsoft.com/SMI/2005/WindowsSettings"
+	windowsSettings2016Namespace = "http://schemas.microsoft.com/SMI/2016/WindowsSettings"
+)
+
+var (
+	requestedExecutionLevels = []string{"asInvoker", "highestAvailable", "requireAdministrator"}
+	utf8BOM                  = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// manifestElement is an element of the manifest with the offsets of its
+// parts in the manifest text, so that it can be edited in place without
+// reformatting the rest of the manifest.
+type manifestElement struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*manifestElement
+
+	// start is the offset of the start tag, startTagEnd is the offset
+	// right after it, contentEnd is the offset of the end tag, and end is
+	// the offset right after the end tag. A self-closing element has all
+	// but the start offsets equal.
+	start       int
+	startTagEnd int
+	contentEnd  int
+	end         int
+}
+
+// manifestPathStep is an element on the path from the root of the manifest.
+// The namespace is declared on the element if it has to be created.
+type manifestPathStep struct {
+	name      string
+	namespace string
+}
+
+// isSelfClosing reports whether the element is written as <name/>.
+func (element *manifestElement) isSelfClosing() bool {
+	return element.end == element.startTagEnd
+}
+
+// child returns the first child element with the given local name, or nil.
+func (element *manifestElement) child(name string) *manifestElement {
+	for _, child := range element.children {
+		if child.name.Local == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// replaceManifest replaces or patches the application manifest according
+// to params.Win.Manifest.
+func (resources *peResources) replaceManifest(binaryPath base.AbsPath, manifest *common.Manifest) error {
+	fmt.Println("Setting manifest for " + binaryPath.String())
+	manifestType := intResourceID(resourceTypeManifest)
+	entries := resources.find(manifestType, intResourceID(manifestResourceID))
+	if len(entries) == 0 {
+		if names := resources.names(manifestType); len(names) != 0 {
+			entries = resources.find(manifestType, names[0])
+		}
+	}
+
+	var replacement []byte
+	if manifest.Path != nil {
+		manifestFile, err := base.FileFromPathString(*manifest.Path)
+		if err != nil {
+			return err
+		}
+		if replacement, err = manifestFile.Read(); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			entries = []*peResource{{typeID: manifestType, nameID: intResourceID(manifestResourceID), language: englishLanguageID}}
+			resources.set(entries[0])
+		}
+	} else if len(entries) == 0 {
+		return errors.New("the file has no manifest, set win.manifest.path to add one")
+	}
+
+	for _, entry := range entries {
+		data := entry.data
+		if replacement != nil {
+			data = replacement
+		}
+		patched, err := patchManifest(data, manifest)
+		if err != nil {
+			return err
+		}
+		entry.data = patched
+	}
+	return nil
+}
+
+// patchManifest applies the changes to the manifest text and checks that
+// the result is well-formed.
+func patchManifest(data []byte, manifest *common.Manifest) ([]byte, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if err := checkManifestWellFormed(data); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if manifest.AssemblyName != nil {
+		fmt.Println("Setting manifest assembly name : " + *manifest.AssemblyName)
+		root, err := parseManifest(data)
+		if err != nil {
+			return nil, err
+		}
+		if root.child("assemblyIdentity") == nil {
+			return nil, errors.New("the manifest has no assemblyIdentity element to rename")
+		}
+		data, err = setManifestAttribute(data, []manifestPathStep{{name: "assemblyIdentity"}}, "name", *manifest.AssemblyName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	windowsSettings := []manifestPathStep{{name: "application", namespace: asmV3Namespace}, {name: "windowsSettings", namespace: asmV3Namespace}}
+	if manifest.DpiAware != nil {
+		fmt.Println("Setting manifest dpiAware : " + *manifest.DpiAware)
+		data, err = setManifestText(data, append(windowsSettings, manifestPathStep{name: "dpiAware", namespace: windowsSettings2005Namespace}), *manifest.DpiAware)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if manifest.DpiAwareness != nil {
+		fmt.Println("Setting manifest dpiAwareness : " + *manifest.DpiAwareness)
+		data, err = setManifestText(data, append(windowsSettings, manifestPathStep{name: "dpiAwareness", namespace: windowsSettings2016Namespace}), *manifest.DpiAwareness)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if manifest.LongPathAware != nil {
+		value := strconv.FormatBool(*manifest.LongPathAware)
+		fmt.Println("Setting manifest longPathAware : " + value)
+		data, err = setManifestText(data, append(windowsSettings, manifestPathStep{name: "longPathAware", namespace: windowsSettings2016Namespace}), value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if manifest.RequestedExecutionLevel != nil {
+		level := *manifest.RequestedExecutionLevel
+		if !base.Contains(requestedExecutionLevels, level) {
+			return nil, fmt.Errorf("invalid requested execution level %q, expected one of %s", level, strings.Join(requestedExecutionLevels, ", "))
+		}
+		fmt.Println("Setting manifest requestedExecutionLevel : " + level)
+		trustInfo := []manifestPathStep{
+			{name: "trustInfo", namespace: asmV3Namespace},
+			{name: "security", namespace: asmV3Namespace},
+			{name: "requestedPrivileges", namespace: asmV3Namespace},
+			{name: "requestedExecutionLevel", namespace: asmV3Namespace},
+		}
+		data, err = setManifestAttribute(data, trustInfo, "level", level)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkManifestWellFormed(data); err != nil {
+		return nil, fmt.Errorf("the patched manifest is invalid: %w", err)
+	}
+	return data, nil
+}
+
+// checkManifestWellFormed checks that the manifest is a well-formed XML
+// document with the assembly root element.
+func checkManifestWellFormed(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	rootName := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("the manifest is not well-formed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && rootName == "" {
+			rootName = start.Name.Local
+		}
+	}
+	if rootName != "assembly" {
+		return errors.New("the manifest has no assembly root element")
+	}
+	return nil
+}
+
+// parseManifest parses the well-formed manifest into the tree of elements.
+func parseManifest(data []byte) (*manifestElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *manifestElement
+	stack := []*manifestElement{}
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			element := &manifestElement{
+				name:        token.Name,
+				attrs:       append([]xml.Attr(nil), token.Attr...),
+				start:       offset,
+				startTagEnd: int(decoder.InputOffset()),
+			}
+			if len(stack) == 0 {
+				root = element
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			element.contentEnd = offset
+			element.end = int(decoder.InputOffset())
+		}
+	}
+	if root == nil {
+		return nil, errors.New("the manifest has no root element")
+	}
+	return root, nil
+}
+
+// ensureManifestElement returns the manifest with the element at the path
+// from the root, creating the missing elements, and the element itself.
+func ensureManifestElement(data []byte, path []manifestPathStep) ([]byte, *manifestElement, error) {
+	root, err := parseManifest(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent := root
+	for i, step := range path {
+		element := parent.child(step.name)
+		if element != nil {
+			parent = element
+			continue
+		}
+
+		// Create the rest of the path as nested elements.
+		created := ""
+		for j := len(path) - 1; j >= i; j-- {
+			startTag := "<" + path[j].name
+			if path[j].namespace != "" {
+				startTag += ` xmlns="` + path[j].namespace + `"`
+			}
+			if created == "" {
+				created = startTag + "/>"
+			} else {
+				created = startTag + ">" + created + "</" + path[j].name + ">"
+			}
+		}
+		data = insertManifestChild(data, parent, created)
+		return ensureManifestElement(data, path)
+	}
+	return data, parent, nil
+}
+
+// setManifestText sets the text content of the element at the path.
+func setManifestText(data []byte, path []manifestPathStep, text string) ([]byte, error) {
+	data, element, err := ensureManifestElement(data, path)
+	if err != nil {
+		return nil, err
+	}
+	escaped := escapeManifestText(text)
+	if element.isSelfClosing() {
+		replacement := manifestStartTag(element, element.attrs, false) + escaped + manifestEndTag(element)
+		return replaceManifestRange(data, element.start, element.end, replacement), nil
+	}
+	return replaceManifestRange(data, element.startTagEnd, element.contentEnd, escaped), nil
+}
+
+// setManifestAttribute sets the attribute of the element at the path.
+func setManifestAttribute(data []byte, path []manifestPathStep, name, value string) ([]byte, error) {
+	data, element, err := ensureManifestElement(data, path)
+	if err != nil {
+		return nil, err
+	}
+	attrs := append([]xml.Attr(nil), element.attrs...)
+	found := false
+	for i := range attrs {
+		if attrs[i].Name.Space == "" && attrs[i].Name.Local == name {
+			attrs[i].Value = value
+			found = true
+		}
+	}
+	if !found {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	}
+	startTag := manifestStartTag(element, attrs, element.isSelfClosing())
+	return replaceManifestRange(data, element.start, element.startTagEnd, startTag), nil
+}
+
+// insertManifestChild inserts the XML text as the last child of the element.
+func insertManifestChild(data []byte, parent *manifestElement, child string) []byte {
+	if parent.isSelfClosing() {
+		replacement := manifestStartTag(parent, parent.attrs, false) + child + manifestEndTag(parent)
+		return replaceManifestRange(data, parent.start, parent.end, replacement)
+	}
+	return replaceManifestRange(data, parent.contentEnd, parent.contentEnd, child)
+}
+
+func manifestStartTag(element *manifestElement, attrs []xml.Attr, selfClosing bool) string {
+	tag := "<" + qualifiedManifestName(element.name)
+	for _, attr := range attrs {
+		tag += " " + qualifiedManifestName(attr.Name) + `="` + escapeManifestText(attr.Value) + `"`
+	}
+	if selfClosing {
+		return tag + "/>"
+	}
+	return tag + ">"
+}
+
+func manifestEndTag(element *manifestElement) string {
+	return "</" + qualifiedManifestName(element.name) + ">"
+}
+
+// qualifiedManifestName returns the name with its prefix as written in the manifest.
+func qualifiedManifestName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+func escapeManifestText(text string) string {
+	var buffer bytes.Buffer
+	_ = xml.EscapeText(&buffer, []byte(text))
+	return buffer.String()
+}
+
+func replaceManifestRange(data []byte, start, end int, replacement string) []byte {
+	result := append([]byte(nil), data[:start]...)
+	result = append(result, replacement...)
+	return append(result, data[end:]...)
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	dosSignature          = "MZ"
+	peSignature           = "PE\x00\x00"
+	peHeaderOffsetField   = 0x3C
+	coffHeaderSize        = 20
+	sectionHeaderSize     = 40
+	optionalHeaderMagic32 = 0x10B
+	optionalHeaderMagic64 = 0x20B
+
+	// The offsets of the fields within the optional header.
+	sizeOfInitializedDataField = 8
+	sectionAlignmentField      = 32
+	fileAlignmentField         = 36
+	sizeOfImageField           = 56
+	checkSumField              = 64
+	dataDirectories32Field     = 96
+	dataDirectories64Field     = 112
+	dataDirectoryEntrySize     = 8
+
+	dataDirectoryResource  = 2
+	dataDirectorySecurity  = 4
+	dataDirectoryBaseReloc = 5
+)
+
+// peSection is a section header of the PE file.
+type peSection struct {
+	// headerOffset is the file offset of the section header.
+	headerOffset   int
+	name           string
+	virtualSize    uint32
+	virtualAddress uint32
+	sizeOfRawData  uint32
+	pointerToRaw   uint32
+}
+
+// virtualSpan returns the size of the section in memory.
+func (section peSection) virtualSpan() uint32 {
+	if section.virtualSize == 0 {
+		return section.sizeOfRawData
+	}
+	return section.virtualSize
+}
+
+// peFile is a Portable Executable file (.exe or .dll) loaded into memory.
+//
+// It provides just enough of the PE format to read and replace the resource
+// section: the section table, the data directories, and the checksum.
+type peFile struct {
+	path string
+	data []byte
+
+	machine              uint16
+	optionalHeaderOffset int
+	dataDirectoryOffset  int
+	dataDirectoryCount   int
+	sectionAlignment     uint32
+	fileAlignment        uint32
+	sections             []peSection
+}
+
+// readPEFile reads and parses the PE file located at path.
+func readPEFile(path string) (*peFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parsePEFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	file.path = path
+	return file, nil
+}
+
+// parsePEFile parses the headers of the PE file contained in data.
+func parsePEFile(data []byte) (*peFile, error) {
+	if len(data) < peHeaderOffsetField+4 || string(data[:2]) != dosSignature {
+		return nil, errors.New("not a PE file: missing the MZ signature")
+	}
+	peOffset := int(binary.LittleEndian.Uint32(data[peHeaderOffsetField:]))
+	if peOffset < 0 || peOffset+len(peSignature)+coffHeaderSize > len(data) || string(data[peOffset:peOffset+4]) != peSignature {
+		return nil, errors.New("not a PE file: missing the PE signature")
+	}
+
+	coff := data[peOffset+len(peSignature):]
+	file := &peFile{data: data}
+	file.machine = binary.LittleEndian.Uint16(coff[0:2])
+	sectionCount := int(binary.LittleEndian.Uint16(coff[2:4]))
+	optionalHeaderSize := int(binary.LittleEndian.Uint16(coff[16:18]))
+	file.optionalHeaderOffset = peOffset + len(peSignature) + coffHeaderSize
+
+	sectionTableOffset := file.optionalHeaderOffset + optionalHeaderSize
+	if sectionTableOffset+sectionCount*sectionHeaderSize > len(data) {
+		return nil, errors.New("the PE headers are truncated")
+	}
+
+	optional := data[file.optionalHeaderOffset:sectionTableOffset]
+	if len(optional) < 2 {
+		return nil, errors.New("the optional header is missing")
+	}
+	switch binary.LittleEndian.Uint16(optional[0:2]) {
+	case optionalHeaderMagic32:
+		file.dataDirectoryOffset = file.optionalHeaderOffset + dataDirectories32Field
+	case optionalHeaderMagic64:
+		file.dataDirectoryOffset = file.optionalHeaderOffset + dataDirectories64Field
+	default:
+		return nil, fmt.Errorf("unsupported optional header magic 0x%X", binary.LittleEndian.Uint16(optional[0:2]))
+	}
+	if file.dataDirectoryOffset > sectionTableOffset {
+		return nil, errors.New("the optional header is truncated")
+	}
+	numberOfRvaAndSizes := int(binary.LittleEndian.Uint32(data[file.dataDirectoryOffset-4:]))
+	file.dataDirectoryCount = (sectionTableOffset - file.dataDirectoryOffset) / dataDirectoryEntrySize
+	if numberOfRvaAndSizes < file.dataDirectoryCount {
+		file.dataDirectoryCount = numberOfRvaAndSizes
+	}
+	file.sectionAlignment = binary.LittleEndian.Uint32(optional[sectionAlignmentField:])
+	file.fileAlignment = binary.LittleEndian.Uint32(optional[fileAlignmentField:])
+
+	for i := 0; i < sectionCount; i++ {
+		offset := sectionTableOffset + i*sectionHeaderSize
+		header := data[offset : offset+sectionHeaderSize]
+		name := header[0:8]
+		for len(name) > 0 && name[len(name)-1] == 0 {
+			name = name[:len(name)-1]
+		}
+		file.sections = append(file.sections, peSection{
+			headerOffset:   offset,
+			name:           string(name),
+			virtualSize:    binary.LittleEndian.Uint32(header[8:12]),
+			virtualAddress: binary.LittleEndian.Uint32(header[12:16]),
+			sizeOfRawData:  binary.LittleEndian.Uint32(header[16:20]),
+			pointerToRaw:   binary.LittleEndian.Uint32(header[20:24]),
+		})
+	}
+	sort.SliceStable(file.sections, func(i, j int) bool {
+		return file.sections[i].virtualAddress < file.sections[j].virtualAddress
+	})
+
+	return file, nil
+}
+
+// dataDirectory returns the RVA (or the file offset for the security directory)
+// and the size of the data directory at index.
+func (file *peFile) dataDirectory(index int) (uint32, uint32) {
+	if index >= file.dataDirectoryCount {
+		return 0, 0
+	}
+	offset := file.dataDirectoryOffset + index*dataDirectoryEntrySize
+	return binary.LittleEndian.Uint32(file.data[offset:]), binary.LittleEndian.Uint32(file.data[offset+4:])
+}
+
+func (file *peFile) setDataDirectory(index int, address, size uint32) {
+	offset := file.dataDirectoryOffset + index*dataDirectoryEntrySize
+	binary.LittleEndian.PutUint32(file.data[offset:], address)
+	binary.LittleEndian.PutUint32(file.data[offset+4:], size)
+}
+
+// sectionIndexForRVA returns the index of the section containing the given RVA, or -1.
+func (file *peFile) sectionIndexForRVA(rva uint32) int {
+	for i, section := range file.sections {
+		if rva >= section.virtualAddress && rva < section.virtualAddress+section.virtualSpan() {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionData returns the raw data of the section, limited to its virtual size.
+func (file *peFile) sectionData(section peSection) ([]byte, error) {
+	size := section.sizeOfRawData
+	if section.virtualSize != 0 && section.virtualSize < size {
+		size = section.virtualSize
+	}
+	start, end := int(section.pointerToRaw), int(section.pointerToRaw)+int(size)
+	if end > len(file.data) {
+		return nil, fmt.Errorf("the %s section exceeds the file size", section.name)
+	}
+	return file.data[start:end], nil
+}
+
+// resourceSection returns the index of the section holding the resources.
+func (file *peFile) resourceSection() (int, error) {
+	rva, size := file.dataDirectory(dataDirectoryResource)
+	if rva == 0 || size == 0 {
+		return -1, errors.New("the file has no resources")
+	}
+	index := file.sectionIndexForRVA(rva)
+	if index < 0 || file.sections[index].virtualAddress != rva {
+		return -1, errors.New("the resource directory does not start a section")
+	}
+	return index, nil
+}
+
+// readResources parses the resource section of the file.
+func (file *peFile) readResources() (*peResources, error) {
+	index, err := file.resourceSection()
+	if err != nil {
+		return nil, err
+	}
+	section := file.sections[index]
+	data, err := file.sectionData(section)
+	if err != nil {
+		return nil, err
+	}
+	return parseResources(data, section.virtualAddress)
+}
+
+// writeResources replaces the resource section of the file with the given
+// resources.
+//
+// If the new section is larger than the old one, the sections following it
+// are moved. This is possible only for the base relocation section, which
+// follows the resource section in the binaries produced by the MSVC and LLVM
+// linkers. The file must not be signed, since the signature would be
+// invalidated anyway.
+func (file *peFile) writeResources(resources *peResources) error {
+	if _, size := file.dataDirectory(dataDirectorySecurity); size != 0 {
+		return errors.New("the file is signed, remove the signature before editing its resources")
+	}
+
+	index, err := file.resourceSection()
+	if err != nil {
+		return err
+	}
+	relocRVA, relocSize := file.dataDirectory(dataDirectoryBaseReloc)
+	relocIndex := -1
+	if relocRVA != 0 {
+		relocIndex = file.sectionIndexForRVA(relocRVA)
+	}
+	for i := index + 1; i < len(file.sections); i++ {
+		if i != relocIndex {
+			return fmt.Errorf("cannot move the %s section that follows the resource section", file.sections[i].name)
+		}
+	}
+
+	oldSections := append([]peSection(nil), file.sections...)
+	rsrc := resources.serialize(file.sections[index].virtualAddress)
+
+	// The end of the raw data of the last section. Anything after it is overlay
+	// data that is kept at the end of the file.
+	rawEnd := uint32(0)
+	for _, section := range oldSections {
+		if end := section.pointerToRaw + section.sizeOfRawData; end > rawEnd {
+			rawEnd = end
+		}
+	}
+	if int(rawEnd) > len(file.data) {
+		return errors.New("the section data exceeds the file size")
+	}
+
+	out := make([]byte, 0, len(file.data)+len(rsrc))
+	out = append(out, file.data[:oldSections[index].pointerToRaw]...)
+
+	// Lay out the resource section and the sections following it.
+	for i := index; i < len(file.sections); i++ {
+		section := &file.sections[i]
+		var content []byte
+		if i == index {
+			content = rsrc
+			section.virtualSize = uint32(len(rsrc))
+		} else {
+			previous := file.sections[i-1]
+			section.virtualAddress = alignUp(previous.virtualAddress+previous.virtualSpan(), file.sectionAlignment)
+			old := oldSections[i]
+			content = file.data[old.pointerToRaw : old.pointerToRaw+old.sizeOfRawData]
+		}
+		section.pointerToRaw = uint32(len(out))
+		section.sizeOfRawData = alignUp(uint32(len(content)), file.fileAlignment)
+		out = append(out, content...)
+		out = append(out, make([]byte, int(section.sizeOfRawData)-len(content))...)
+	}
+	out = append(out, file.data[rawEnd:]...)
+	file.data = out
+
+	for i := index; i < len(file.sections); i++ {
+		section := file.sections[i]
+		binary.LittleEndian.PutUint32(file.data[section.headerOffset+8:], section.virtualSize)
+		binary.LittleEndian.PutUint32(file.data[section.headerOffset+12:], section.virtualAddress)
+		binary.LittleEndian.PutUint32(file.data[section.headerOffset+16:], section.sizeOfRawData)
+		binary.LittleEndian.PutUint32(file.data[section.headerOffset+20:], section.pointerToRaw)
+	}
+
+	file.setDataDirectory(dataDirectoryResource, file.sections[index].virtualAddress, uint32(len(rsrc)))
+	if relocIndex > index {
+		newRelocRVA := relocRVA - oldSections[relocIndex].virtualAddress + file.sections[relocIndex].virtualAddress
+		file.setDataDirectory(dataDirectoryBaseReloc, newRelocRVA, relocSize)
+	}
+
+	last := file.sections[len(file.sections)-1]
+	sizeOfImage := alignUp(last.virtualAddress+last.virtualSpan(), file.sectionAlignment)
+	binary.LittleEndian.PutUint32(file.data[file.optionalHeaderOffset+sizeOfImageField:], sizeOfImage)
+
+	sizeOfInitializedData := binary.LittleEndian.Uint32(file.data[file.optionalHeaderOffset+sizeOfInitializedDataField:])
+	sizeOfInitializedData += file.sections[index].sizeOfRawData - oldSections[index].sizeOfRawData
+	binary.LittleEndian.PutUint32(file.data[file.optionalHeaderOffset+sizeOfInitializedDataField:], sizeOfInitializedData)
+
+	return nil
+}
+
+// updateChecksum recomputes the checksum stored in the optional header.
+func (file *peFile) updateChecksum() {
+	checksumOffset := file.optionalHeaderOffset + checkSumField
+	binary.LittleEndian.PutUint32(file.data[checksumOffset:], peChecksum(file.data, checksumOffset))
+}
+
+// save recomputes the checksum and writes the file back to its path.
+func (file *peFile) save() error {
+	file.updateChecksum()
+	info, err := os.Stat(file.path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file.path, file.data, info.Mode())
+}
+
+// peChecksum computes the PE image checksum as done by CheckSumMappedFile:
+// the 16-bit one's complement sum of the file, excluding the checksum field
+// itself, plus the file length.
+func peChecksum(data []byte, checksumOffset int) uint32 {
+	var sum uint64
+	for i := 0; i+1 < len(data); i += 2 {
+		if i == checksumOffset || i == checksumOffset+2 {
+			continue
+		}
+		sum += uint64(binary.LittleEndian.Uint16(data[i:]))
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	if len(data)%2 == 1 {
+		sum += uint64(data[len(data)-1])
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	sum = (sum & 0xFFFF) + (sum >> 16)
+	return uint32(sum) + uint32(len(data))
+}
+
+func alignUp(value, alignment uint32) uint32 {
+	if alignment == 0 {
+		return value
+	}
+	return (value + alignment - 1) / alignment * alignment
+}
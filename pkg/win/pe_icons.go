@@ -0,0 +1,144 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+const (
+	groupIconDirSize   = 6
+	groupIconEntrySize = 14
+)
+
+// iconGroupIDs returns the ids of the RT_GROUP_ICON resources in the directory order.
+func (resources *peResources) iconGroupIDs() []resourceID {
+	return resources.names(intResourceID(resourceTypeGroupIcon))
+}
+
+// replaceIconGroup replaces every language variant of the icon group with
+// the images of the ICO file data. The variants share the new images.
+//
+// The RT_ICON resources of the previous images are removed unless another icon
+// group refers to them, and the new images get the freed or the next unused ids.
+func (resources *peResources) replaceIconGroup(groupID resourceID, ico []byte) error {
+	entries, err := parseICO(ico)
+	if err != nil {
+		return err
+	}
+	groups := resources.find(intResourceID(resourceTypeGroupIcon), groupID)
+	if len(groups) == 0 {
+		return fmt.Errorf("no icon group %s", groupID)
+	}
+
+	// Collect the icons referenced by the groups being replaced and by the others.
+	replaced := map[uint16]bool{}
+	referencedElsewhere := map[uint16]bool{}
+	for _, group := range resources.ofType(intResourceID(resourceTypeGroupIcon)) {
+		ids, err := groupIconIDs(group.data)
+		if err != nil {
+			return fmt.Errorf("invalid icon group %s: %w", group.nameID, err)
+		}
+		for _, id := range ids {
+			if group.nameID == groupID {
+				replaced[id] = true
+			} else {
+				referencedElsewhere[id] = true
+			}
+		}
+	}
+
+	// The next unused id is found before the icons are removed, so that it
+	// is never one of the freed ids.
+	nextID := uint16(1)
+	for _, icon := range resources.ofType(intResourceID(resourceTypeIcon)) {
+		if !icon.nameID.isNamed() && icon.nameID.id >= nextID {
+			nextID = icon.nameID.id + 1
+		}
+	}
+
+	freeIDs := []uint16{}
+	for id := range replaced {
+		if !referencedElsewhere[id] {
+			resources.remove(intResourceID(resourceTypeIcon), intResourceID(id))
+			freeIDs = append(freeIDs, id)
+		}
+	}
+	sort.Slice(freeIDs, func(i, j int) bool { return freeIDs[i] < freeIDs[j] })
+
+	allocateID := func() uint16 {
+		if len(freeIDs) > 0 {
+			id := freeIDs[0]
+			freeIDs = freeIDs[1:]
+			return id
+		}
+		nextID++
+		return nextID - 1
+	}
+
+	// The images are stored once, in the language of the first variant, and
+	// every language variant of the group refers to the same ids.
+	data := make([]byte, groupIconDirSize+len(entries)*groupIconEntrySize)
+	binary.LittleEndian.PutUint16(data[2:4], 1)
+	binary.LittleEndian.PutUint16(data[4:6], uint16(len(entries)))
+	for i, e := range entries {
+		id := allocateID()
+		resources.set(&peResource{
+			typeID:   intResourceID(resourceTypeIcon),
+			nameID:   intResourceID(id),
+			language: groups[0].language,
+			codePage: groups[0].codePage,
+			data:     e.Data,
+		})
+
+		p := groupIconDirSize + i*groupIconEntrySize
+		data[p+0] = e.WidthByte
+		data[p+1] = e.HeightByte
+		data[p+2] = e.ColorCount
+		binary.LittleEndian.PutUint16(data[p+4:p+6], e.PlanesOrHotspotX)
+		binary.LittleEndian.PutUint16(data[p+6:p+8], e.BitCountOrHotY)
+		binary.LittleEndian.PutUint32(data[p+8:p+12], uint32(len(e.Data)))
+		binary.LittleEndian.PutUint16(data[p+12:p+14], id)
+	}
+	for _, group := range groups {
+		group.data = append([]byte(nil), data...)
+	}
+	return nil
+}
+
+// groupIconIDs returns the ids of the RT_ICON resources the GRPICONDIR data refers to.
+func groupIconIDs(data []byte) ([]uint16, error) {
+	if len(data) < groupIconDirSize {
+		return nil, fmt.Errorf("the group is too small")
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if len(data) < groupIconDirSize+count*groupIconEntrySize {
+		return nil, fmt.Errorf("the group entries are truncated")
+	}
+	ids := make([]uint16, 0, count)
+	for i := 0; i < count; i++ {
+		p := groupIconDirSize + i*groupIconEntrySize
+		ids = append(ids, binary.LittleEndian.Uint16(data[p+12:p+14]))
+	}
+	return ids, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"fmt"
+	"image"
+	"testing"
+)
+
+func testICO(t *testing.T, sizes ...int) []byte {
+	t.Helper()
+	images := []image.Image{}
+	for _, size := range sizes {
+		images = append(images, image.NewNRGBA(image.Rect(0, 0, size, size)))
+	}
+	ico, err := encodeICO(images)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ico
+}
+
+func TestReplaceIconGroupGrowsGroup(t *testing.T) {
+	resources := &peResources{}
+	resources.set(&peResource{
+		typeID:   intResourceID(resourceTypeGroupIcon),
+		nameID:   intResourceID(101),
+		language: 1033,
+		data:     make([]byte, groupIconDirSize),
+	})
+	if err := resources.replaceIconGroup(intResourceID(101), testICO(t, 16, 32, 48)); err != nil {
+		t.Fatal(err)
+	}
+	if err := resources.replaceIconGroup(intResourceID(101), testICO(t, 16, 24, 32, 48, 256)); err != nil {
+		t.Fatal(err)
+	}
+
+	group := resources.find(intResourceID(resourceTypeGroupIcon), intResourceID(101))[0]
+	ids, err := groupIconIDs(group.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 5 {
+		t.Fatalf("expected 5 icons in the group, got %v", ids)
+	}
+	seen := map[uint16]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("the icon id %d is used twice: %v", id, ids)
+		}
+		seen[id] = true
+		if len(resources.find(intResourceID(resourceTypeIcon), intResourceID(id))) != 1 {
+			t.Fatalf("no icon resource %d", id)
+		}
+	}
+	if icons := resources.ofType(intResourceID(resourceTypeIcon)); len(icons) != 5 {
+		t.Fatalf("expected 5 icon resources, got %d", len(icons))
+	}
+}
+
+func TestReplaceIconGroupSharesImagesBetweenLanguages(t *testing.T) {
+	resources := &peResources{}
+	for _, language := range []uint16{1033, 1049} {
+		resources.set(&peResource{
+			typeID:   intResourceID(resourceTypeGroupIcon),
+			nameID:   intResourceID(101),
+			language: language,
+			data:     make([]byte, groupIconDirSize),
+		})
+	}
+	if err := resources.replaceIconGroup(intResourceID(101), testICO(t, 16, 32, 48)); err != nil {
+		t.Fatal(err)
+	}
+
+	groups := resources.find(intResourceID(resourceTypeGroupIcon), intResourceID(101))
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 language variants, got %d", len(groups))
+	}
+	first, err := groupIconIDs(groups[0].data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := groupIconIDs(groups[1].data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatalf("the language variants refer to different icons: %v and %v", first, second)
+	}
+	if icons := resources.ofType(intResourceID(resourceTypeIcon)); len(icons) != 3 {
+		t.Fatalf("expected 3 icon resources, got %d", len(icons))
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	resourceTypeIcon      = 3
+	resourceTypeGroupIcon = 14
+	resourceTypeVersion   = 16
+	resourceTypeManifest  = 24
+
+	resourceDirectorySize      = 16
+	resourceDirectoryEntrySize = 8
+	resourceDataEntrySize      = 16
+	resourceDataAlignment      = 8
+
+	// resourceSubdirectoryFlag marks the directory entries pointing to subdirectories
+	// and the entries identified by name rather than by number.
+	resourceSubdirectoryFlag = 0x80000000
+)
+
+// resourceID identifies a resource type or a resource name, either by
+// a number or by a string.
+type resourceID struct {
+	name string
+	id   uint16
+}
+
+// intResourceID returns the resourceID for the numeric identifier.
+func intResourceID(id uint16) resourceID {
+	return resourceID{id: id}
+}
+
+// parseResourceID parses the string representation of the resourceID: a number
+// for the numeric identifiers and the name otherwise.
+func parseResourceID(value string) resourceID {
+	if id, err := strconv.ParseUint(value, 10, 16); err == nil {
+		return intResourceID(uint16(id))
+	}
+	return resourceID{name: strings.ToUpper(value)}
+}
+
+// isNamed reports whether the resource is identified by a string.
+func (id resourceID) isNamed() bool {
+	return id.name != ""
+}
+
+func (id resourceID) String() string {
+	if id.isNamed() {
+		return id.name
+	}
+	return strconv.Itoa(int(id.id))
+}
+
+// less orders the resource identifiers as required in the resource directory:
+// the named entries come first in the ascending order, followed by
+// the numeric ones.
+func (id resourceID) less(other resourceID) bool {
+	if id.isNamed() != other.isNamed() {
+		return id.isNamed()
+	}
+	if id.isNamed() {
+		return strings.ToUpper(id.name) < strings.ToUpper(other.name)
+	}
+	return id.id < other.id
+}
+
+// peResource is a single resource of the PE file identified by its type,
+// name, and language.
+type peResource struct {
+	typeID   resourceID
+	nameID   resourceID
+	language uint16
+	codePage uint32
+	data     []byte
+}
+
+// peResources is the list of the resources stored in the resource section
+// of the PE file.
+type peResources struct {
+	entries []*peResource
+}
+
+// parseResources reads the resource directory tree from the resource section
+// data. The section is mapped at sectionRVA, which is needed to locate the data
+// of the resources.
+func parseResources(section []byte, sectionRVA uint32) (*peResources, error) {
+	resources := &peResources{}
+	err := walkResourceDirectory(section, 0, 0, func(path [3]resourceID, dataEntryOffset uint32) error {
+		if int(dataEntryOffset)+resourceDataEntrySize > len(section) {
+			return errors.New("the resource data entry is out of bounds")
+		}
+		entry := section[dataEntryOffset:]
+		dataRVA := binary.LittleEndian.Uint32(entry[0:4])
+		size := binary.LittleEndian.Uint32(entry[4:8])
+		start := uint64(dataRVA) - uint64(sectionRVA)
+		if dataRVA < sectionRVA || start+uint64(size) > uint64(len(section)) {
+			return fmt.Errorf("the data of the resource %s/%s is out of the resource section", path[0], path[1])
+		}
+		resources.entries = append(resources.entries, &peResource{
+			typeID:   path[0],
+			nameID:   path[1],
+			language: path[2].id,
+			codePage: binary.LittleEndian.Uint32(entry[8:12]),
+			data:     append([]byte(nil), section[start:start+uint64(size)]...),
+		})
+		return nil
+	}, [3]resourceID{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource section: %w", err)
+	}
+	return resources, nil
+}
+
+// walkResourceDirectory visits the data entries of the resource directory
+// located at offset. The level is 0 for types, 1 for names, and 2 for languages.
+func walkResourceDirectory(section []byte, offset uint32, level int, visit func([3]resourceID, uint32) error, path [3]resourceID) error {
+	if level > 2 {
+		return errors.New("the resource directory is too deep")
+	}
+	if int(offset)+resourceDirectorySize > len(section) {
+		return errors.New("the resource directory is out of bounds")
+	}
+	named := int(binary.LittleEndian.Uint16(section[offset+12:]))
+	numbered := int(binary.LittleEndian.Uint16(section[offset+14:]))
+	entriesOffset := int(offset) + resourceDirectorySize
+	if entriesOffset+(named+numbered)*resourceDirectoryEntrySize > len(section) {
+		return errors.New("the resource directory entries are out of bounds")
+	}
+
+	for i := 0; i < named+numbered; i++ {
+		entry := section[entriesOffset+i*resourceDirectoryEntrySize:]
+		nameField := binary.LittleEndian.Uint32(entry[0:4])
+		dataField := binary.LittleEndian.Uint32(entry[4:8])
+
+		var id resourceID
+		if nameField&resourceSubdirectoryFlag != 0 {
+			name, err := readResourceString(section, nameField&^resourceSubdirectoryFlag)
+			if err != nil {
+				return err
+			}
+			id = resourceID{name: name}
+		} else {
+			id = intResourceID(uint16(nameField))
+		}
+		path[level] = id
+
+		if dataField&resourceSubdirectoryFlag != 0 {
+			if err := walkResourceDirectory(section, dataField&^resourceSubdirectoryFlag, level+1, visit, path); err != nil {
+				return err
+			}
+		} else if level == 2 {
+			if err := visit(path, dataField); err != nil {
+				return err
+			}
+		} else {
+			return errors.New("the resource directory is too shallow")
+		}
+	}
+	return nil
+}
+
+// readResourceString reads the length-prefixed UTF-16 string at offset.
+func readResourceString(section []byte, offset uint32) (string, error) {
+	if int(offset)+2 > len(section) {
+		return "", errors.New("the resource name is out of bounds")
+	}
+	length := int(binary.LittleEndian.Uint16(section[offset:]))
+	if int(offset)+2+length*2 > len(section) {
+		return "", errors.New("the resource name is out of bounds")
+	}
+	chars := make([]uint16, length)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(section[int(offset)+2+i*2:])
+	}
+	return string(utf16.Decode(chars)), nil
+}
+
+// ofType returns all the resources of the given type.
+func (resources *peResources) ofType(typeID resourceID) []*peResource {
+	result := []*peResource{}
+	for _, resource := range resources.entries {
+		if resource.typeID == typeID {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+// find returns all the language variants of the resource.
+func (resources *peResources) find(typeID, nameID resourceID) []*peResource {
+	result := []*peResource{}
+	for _, resource := range resources.entries {
+		if resource.typeID == typeID && resource.nameID == nameID {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+// names returns the unique names of the resources of the given type in
+// the directory order.
+func (resources *peResources) names(typeID resourceID) []resourceID {
+	seen := map[resourceID]bool{}
+	names := []resourceID{}
+	for _, resource := range resources.ofType(typeID) {
+		if !seen[resource.nameID] {
+			seen[resource.nameID] = true
+			names = append(names, resource.nameID)
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool { return names[i].less(names[j]) })
+	return names
+}
+
+// set adds the resource or replaces the existing one with the same type,
+// name, and language.
+func (resources *peResources) set(resource *peResource) {
+	for i, existing := range resources.entries {
+		if existing.typeID == resource.typeID && existing.nameID == resource.nameID && existing.language == resource.language {
+			resources.entries[i] = resource
+			return
+		}
+	}
+	resources.entries = append(resources.entries, resource)
+}
+
+// remove deletes all the language variants of the resource.
+func (resources *peResources) remove(typeID, nameID resourceID) {
+	kept := resources.entries[:0]
+	for _, resource := range resources.entries {
+		if resource.typeID != typeID || resource.nameID != nameID {
+			kept = append(kept, resource)
+		}
+	}
+	resources.entries = kept
+}
+
+// serialize builds the resource section data for the section mapped at sectionRVA.
+//
+// The section starts with the directory tables of all three levels, followed
+// by the data entries, the resource name strings, and the resource data
+// aligned to 8 bytes.
+func (resources *peResources) serialize(sectionRVA uint32) []byte {
+	type nameNode struct {
+		id        resourceID
+		resources []*peResource
+	}
+	type typeNode struct {
+		id    resourceID
+		names []*nameNode
+	}
+
+	types := []*typeNode{}
+	for _, resource := range resources.entries {
+		var typeEntry *typeNode
+		for _, t := range types {
+			if t.id == resource.typeID {
+				typeEntry = t
+			}
+		}
+		if typeEntry == nil {
+			typeEntry = &typeNode{id: resource.typeID}
+			types = append(types, typeEntry)
+		}
+		var nameEntry *nameNode
+		for _, n := range typeEntry.names {
+			if n.id == resource.nameID {
+				nameEntry = n
+			}
+		}
+		if nameEntry == nil {
+			nameEntry = &nameNode{id: resource.nameID}
+			typeEntry.names = append(typeEntry.names, nameEntry)
+		}
+		nameEntry.resources = append(nameEntry.resources, resource)
+	}
+
+	sort.SliceStable(types, func(i, j int) bool { return types[i].id.less(types[j].id) })
+	resourceCount := 0
+	for _, t := range types {
+		sort.SliceStable(t.names, func(i, j int) bool { return t.names[i].id.less(t.names[j].id) })
+		for _, n := range t.names {
+			sort.SliceStable(n.resources, func(i, j int) bool { return n.resources[i].language < n.resources[j].language })
+			resourceCount += len(n.resources)
+		}
+	}
+
+	directorySize := func(entries int) uint32 {
+		return uint32(resourceDirectorySize + entries*resourceDirectoryEntrySize)
+	}
+
+	// Compute the layout of the section.
+	offset := directorySize(len(types))
+	typeOffsets := map[*typeNode]uint32{}
+	for _, t := range types {
+		typeOffsets[t] = offset
+		offset += directorySize(len(t.names))
+	}
+	nameOffsets := map[*nameNode]uint32{}
+	for _, t := range types {
+		for _, n := range t.names {
+			nameOffsets[n] = offset
+			offset += directorySize(len(n.resources))
+		}
+	}
+	dataEntriesOffset := offset
+	offset += uint32(resourceCount * resourceDataEntrySize)
+
+	stringOffsets := map[string]uint32{}
+	stringTable := []string{}
+	addString := func(id resourceID) {
+		if _, ok := stringOffsets[id.name]; id.isNamed() && !ok {
+			stringOffsets[id.name] = offset
+			stringTable = append(stringTable, id.name)
+			offset += uint32(2 + 2*len(utf16.Encode([]rune(id.name))))
+		}
+	}
+	for _, t := range types {
+		addString(t.id)
+		for _, n := range t.names {
+			addString(n.id)
+		}
+	}
+
+	dataOffsets := make([]uint32, 0, resourceCount)
+	for _, t := range types {
+		for _, n := range t.names {
+			for _, resource := range n.resources {
+				offset = alignUp(offset, resourceDataAlignment)
+				dataOffsets = append(dataOffsets, offset)
+				offset += uint32(len(resource.data))
+			}
+		}
+	}
+	out := make([]byte, alignUp(offset, resourceDataAlignment))
+
+	writeDirectory := func(at uint32, ids []resourceID, targets []uint32, subdirectories bool) {
+		named := 0
+		for _, id := range ids {
+			if id.isNamed() {
+				named++
+			}
+		}
+		binary.LittleEndian.PutUint16(out[at+12:], uint16(named))
+		binary.LittleEndian.PutUint16(out[at+14:], uint16(len(ids)-named))
+		for i, id := range ids {
+			entry := out[at+resourceDirectorySize+uint32(i*resourceDirectoryEntrySize):]
+			if id.isNamed() {
+				binary.LittleEndian.PutUint32(entry[0:4], stringOffsets[id.name]|resourceSubdirectoryFlag)
+			} else {
+				binary.LittleEndian.PutUint32(entry[0:4], uint32(id.id))
+			}
+			target := targets[i]
+			if subdirectories {
+				target |= resourceSubdirectoryFlag
+			}
+			binary.LittleEndian.PutUint32(entry[4:8], target)
+		}
+	}
+
+	typeIDs, typeTargets := []resourceID{}, []uint32{}
+	for _, t := range types {
+		typeIDs = append(typeIDs, t.id)
+		typeTargets = append(typeTargets, typeOffsets[t])
+	}
+	writeDirectory(0, typeIDs, typeTargets, true)
+
+	dataIndex := 0
+	for _, t := range types {
+		nameIDs, nameTargets := []resourceID{}, []uint32{}
+		for _, n := range t.names {
+			nameIDs = append(nameIDs, n.id)
+			nameTargets = append(nameTargets, nameOffsets[n])
+
+			languageIDs, dataEntryTargets := []resourceID{}, []uint32{}
+			for _, resource := range n.resources {
+				dataEntryOffset := dataEntriesOffset + uint32(dataIndex*resourceDataEntrySize)
+				languageIDs = append(languageIDs, intResourceID(resource.language))
+				dataEntryTargets = append(dataEntryTargets, dataEntryOffset)
+
+				dataEntry := out[dataEntryOffset:]
+				binary.LittleEndian.PutUint32(dataEntry[0:4], sectionRVA+dataOffsets[dataIndex])
+				binary.LittleEndian.PutUint32(dataEntry[4:8], uint32(len(resource.data)))
+				binary.LittleEndian.PutUint32(dataEntry[8:12], resource.codePage)
+				copy(out[dataOffsets[dataIndex]:], resource.data)
+				dataIndex++
+			}
+			writeDirectory(nameOffsets[n], languageIDs, dataEntryTargets, false)
+		}
+		writeDirectory(typeOffsets[t], nameIDs, nameTargets, true)
+	}
+
+	for _, name := range stringTable {
+		chars := utf16.Encode([]rune(name))
+		at := stringOffsets[name]
+		binary.LittleEndian.PutUint16(out[at:], uint16(len(chars)))
+		for i, char := range chars {
+			binary.LittleEndian.PutUint16(out[at+2+uint32(i*2):], char)
+		}
+	}
+
+	return out
+}
@@ -21,60 +21,338 @@
 package win
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/base"
 	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
 )
 
-const binaryFilePathPlaceholder = "@@BINARY_PATH@@"
+// cmdBatchSize is the number of files passed to a batch command run by `cmd`
+// if the batch size is not set. It keeps the command line of the paths up
+// to MAX_PATH characters within the 8191 characters `cmd` accepts.
+const cmdBatchSize = 20
+
+const (
+	binaryFilePathPlaceholder  = "@@BINARY_PATH@@"
+	binaryPathsPlaceholder     = "@@BINARY_PATHS@@"
+	fileNamePlaceholder        = "@@FILE_NAME@@"
+	productNamePlaceholder     = "@@PRODUCT_NAME@@"
+	productUrlPlaceholder      = "@@PRODUCT_URL@@"
+	fileDescriptionPlaceholder = "@@FILE_DESCRIPTION@@"
+)
 
 // The sign tool on Windows is defined by the custom user commands.
 //
 // To create one, use `GetSignToolWin`.
 type SignToolWin struct {
-	signCommandTemplate string
+	signCommand *common.SignCommand
+	productName string
+	productUrl  string
+
+	// batchSize is the maximum number of files passed to a batch command,
+	// or 0 if not set.
+	batchSize int
+}
+
+// placeholderValue is the value substituted instead of the placeholder.
+type placeholderValue struct {
+	placeholder string
+	value       string
 }
 
 func GetSignToolWin(params common.BrandingParams) (*SignToolWin, error) {
-	if params.Win.SignCommand == "" {
+	if params.Win.SignCommand == nil || len(params.Win.SignCommand.Commands) == 0 {
 		return nil, errors.New("the sign command is empty")
 	}
-	if !canSubstituteBinaryPath(params.Win.SignCommand) {
-		return nil, fmt.Errorf("the sign command is invalid: requires %s placeholder", binaryFilePathPlaceholder)
+	for _, command := range params.Win.SignCommand.Commands {
+		if _, err := path.Match(strings.ToLower(command.Glob), ""); err != nil {
+			return nil, fmt.Errorf("invalid sign command glob %s: %w", command.Glob, err)
+		}
+		if strings.TrimSpace(strings.Join(command.Command.Strings(), "")) == "" {
+			return nil, fmt.Errorf("the sign command for %s is empty", command.Glob)
+		}
+		if isBatchCommand(command.Command) {
+			for _, placeholder := range []string{binaryFilePathPlaceholder, fileNamePlaceholder, fileDescriptionPlaceholder} {
+				if containsPlaceholder(command.Command, placeholder) {
+					return nil, fmt.Errorf("the sign command for %s is invalid: %s cannot be used with %s", command.Glob, placeholder, binaryPathsPlaceholder)
+				}
+			}
+			if command.Command.Argv == nil && isQuoted(command.Command.Line, binaryPathsPlaceholder) {
+				return nil, fmt.Errorf("the sign command for %s is invalid: %s cannot be in quotes, the paths are quoted if necessary", command.Glob, binaryPathsPlaceholder)
+			}
+		} else if !containsPlaceholder(command.Command, binaryFilePathPlaceholder) {
+			return nil, fmt.Errorf("the sign command for %s is invalid: requires %s or %s placeholder", command.Glob, binaryFilePathPlaceholder, binaryPathsPlaceholder)
+		}
+	}
+	if params.Win.SignBatchSize != nil && *params.Win.SignBatchSize < 1 {
+		return nil, fmt.Errorf("invalid sign batch size %d", *params.Win.SignBatchSize)
 	}
 
-	return &SignToolWin{params.Win.SignCommand}, nil
+	tool := &SignToolWin{signCommand: params.Win.SignCommand, productName: originalChromiumExeName}
+	if params.Win.ProductName != nil {
+		tool.productName = *params.Win.ProductName
+	} else if params.Win.ExecutableName != nil {
+		tool.productName = *params.Win.ExecutableName
+	}
+	if params.Win.ProductUrl != nil {
+		tool.productUrl = *params.Win.ProductUrl
+	}
+	if params.Win.SignBatchSize != nil {
+		tool.batchSize = *params.Win.SignBatchSize
+	}
+	return tool, nil
 }
 
 func (tool *SignToolWin) SignBinary(binaryPath string) error {
-	return tool.execCommand(substituteBinaryPath(tool.signCommandTemplate, binaryPath), binaryPath)
+	return tool.SignBinaries([]string{binaryPath})
 }
 
-// Executes the given `command` with the `binaryPath` substituted.
-//
-// If the command is `nil`, this is just no-op.
-func (tool *SignToolWin) execCommand(command string, binaryPath string) error {
-	if _, err := os.Stat(binaryPath); err != nil {
-		return err
+// SignBinaries signs the binaries with the commands of the globs matching
+// their file names, invoking every command as few times as possible.
+func (tool *SignToolWin) SignBinaries(binaryPaths []string) error {
+	for _, batch := range tool.Batches(binaryPaths) {
+		if err := tool.signBatch(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Batches splits the binaries into the groups signed by a single invocation
+// of a sign command. The files matching a batch command are grouped by at most
+// the batch size, other files are signed one by one.
+func (tool *SignToolWin) Batches(binaryPaths []string) [][]string {
+	batches := [][]string{}
+	batchCommandPaths := make([][]string, len(tool.signCommand.Commands))
+	for _, binaryPath := range binaryPaths {
+		index := tool.commandIndexFor(binaryPath)
+		if index >= 0 && isBatchCommand(tool.signCommand.Commands[index].Command) {
+			batchCommandPaths[index] = append(batchCommandPaths[index], binaryPath)
+		} else {
+			batches = append(batches, []string{binaryPath})
+		}
 	}
 
-	if err := base.ExecCommand(command, []string{}); err != nil {
+	for index, paths := range batchCommandPaths {
+		batchSize := tool.batchSizeFor(tool.signCommand.Commands[index].Command)
+		for start := 0; start < len(paths); {
+			end := len(paths)
+			if batchSize > 0 && start+batchSize < end {
+				end = start + batchSize
+			}
+			batches = append(batches, paths[start:end])
+			start = end
+		}
+	}
+	return batches
+}
+
+// batchSizeFor returns the maximum number of files passed to the batch
+// command, or 0 if unlimited. A command line run by `cmd` is limited to
+// cmdBatchSize files unless the batch size is set.
+func (tool *SignToolWin) batchSizeFor(command common.CommandLine) int {
+	if tool.batchSize == 0 && command.Argv == nil && runtime.GOOS == "windows" {
+		return cmdBatchSize
+	}
+	return tool.batchSize
+}
+
+// signBatch signs the binaries produced by Batches. After a batch command,
+// every file is checked to have a new signature.
+func (tool *SignToolWin) signBatch(binaryPaths []string) error {
+	for _, binaryPath := range binaryPaths {
+		if _, err := os.Stat(binaryPath); err != nil {
+			return err
+		}
+	}
+	index := tool.commandIndexFor(binaryPaths[0])
+	if index < 0 {
+		return fmt.Errorf("no sign command matches %s", binaryPaths[0])
+	}
+
+	command := tool.signCommand.Commands[index].Command
+	if !isBatchCommand(command) {
+		return tool.execCommand(command, tool.placeholderValues(binaryPaths[0]), nil)
+	}
+	previousHashes := [][]byte{}
+	for _, binaryPath := range binaryPaths {
+		hash, err := certificateTableHash(binaryPath)
+		if err != nil {
+			return err
+		}
+		previousHashes = append(previousHashes, hash)
+	}
+	fmt.Printf("Signing %d files with a single command\n", len(binaryPaths))
+	if err := tool.execCommand(command, tool.commonPlaceholderValues(), binaryPaths); err != nil {
 		return err
 	}
+	return checkSigned(binaryPaths, previousHashes)
+}
+
+// CanSign tells whether a glob of the sign command matches the file name
+// of the binary. The other files are not signed.
+func (tool *SignToolWin) CanSign(binaryPath string) bool {
+	return tool.commandIndexFor(binaryPath) >= 0
+}
+
+// commandIndexFor returns the index of the command of the first glob matching
+// the file name of the binary, or -1.
+func (tool *SignToolWin) commandIndexFor(binaryPath string) int {
+	fileName := strings.ToLower(filepath.Base(binaryPath))
+	for i, command := range tool.signCommand.Commands {
+		if matched, _ := path.Match(strings.ToLower(command.Glob), fileName); matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkSigned returns an error listing the binaries that have no signature,
+// or whose certificate table has the previous hash, e.g. because the command
+// skipped the already signed files.
+func checkSigned(binaryPaths []string, previousHashes [][]byte) error {
+	unsigned := []string{}
+	for i, binaryPath := range binaryPaths {
+		if hash, err := certificateTableHash(binaryPath); err != nil {
+			return err
+		} else if hash == nil || bytes.Equal(hash, previousHashes[i]) {
+			unsigned = append(unsigned, binaryPath)
+		}
+	}
+	if len(unsigned) != 0 {
+		return fmt.Errorf("the sign command has not signed %s", strings.Join(unsigned, ", "))
+	}
 	return nil
 }
 
-// Indicates if the given `commandTemplate` contains the `binaryFilePathPlaceholder`
-// to substitute a value instead.
-func canSubstituteBinaryPath(commandTemplate string) bool {
-	return strings.Contains(commandTemplate, binaryFilePathPlaceholder)
+// placeholderValues returns the values of the placeholders for the binary.
+// The file description is the FileDescription of the binary, or the product
+// name if the binary has none.
+func (tool *SignToolWin) placeholderValues(binaryPath string) []placeholderValue {
+	description := fileDescription(binaryPath)
+	if description == "" {
+		description = tool.productName
+	}
+	return append(tool.commonPlaceholderValues(),
+		placeholderValue{binaryFilePathPlaceholder, binaryPath},
+		placeholderValue{fileNamePlaceholder, filepath.Base(binaryPath)},
+		placeholderValue{fileDescriptionPlaceholder, description},
+	)
 }
 
-// Substitutes the given `binaryPath` instead of the `binaryFilePathPlaceholder` into the `commandTemplate`.
-func substituteBinaryPath(commandTemplate string, binaryPath string) string {
-	return strings.Replace(commandTemplate, binaryFilePathPlaceholder, binaryPath, -1)
+// commonPlaceholderValues returns the values of the placeholders that do not
+// depend on the binary.
+func (tool *SignToolWin) commonPlaceholderValues() []placeholderValue {
+	return []placeholderValue{
+		{productNamePlaceholder, tool.productName},
+		{productUrlPlaceholder, tool.productUrl},
+	}
+}
+
+// Executes the given `command` with the placeholders substituted. The `binaryPaths`
+// are substituted instead of the `binaryPathsPlaceholder`.
+//
+// The arguments of the command array are passed as is, and the argument consisting
+// of the `binaryPathsPlaceholder` only expands into an argument per path. The command
+// line is run by `cmd` on Windows, where the values of the placeholders are quoted
+// unless the placeholders are already in quotes. On other platforms, the command line
+// is split into the arguments before the substitution, so the values are never split.
+func (tool *SignToolWin) execCommand(command common.CommandLine, values []placeholderValue, binaryPaths []string) error {
+	if command.Argv == nil && runtime.GOOS == "windows" {
+		quotedPaths := []string{}
+		for _, binaryPath := range binaryPaths {
+			quotedPaths = append(quotedPaths, quoteForCmd(binaryPath))
+		}
+		line := strings.ReplaceAll(command.Line, binaryPathsPlaceholder, strings.Join(quotedPaths, " "))
+		return base.ExecCommand(substitutePlaceholders(line, values, true), []string{})
+	}
+
+	args := command.Argv
+	if args == nil {
+		var err error
+		if args, err = base.SplitCommandLine(command.Line); err != nil {
+			return err
+		}
+	}
+	values = append(values, placeholderValue{binaryPathsPlaceholder, strings.Join(binaryPaths, " ")})
+	substituted := []string{}
+	for _, arg := range args {
+		if arg == binaryPathsPlaceholder {
+			substituted = append(substituted, binaryPaths...)
+		} else {
+			substituted = append(substituted, substitutePlaceholders(arg, values, false))
+		}
+	}
+	return base.ExecCommand(substituted[0], substituted[1:])
+}
+
+// Indicates if the given `command` contains the `placeholder` to substitute a value instead.
+func containsPlaceholder(command common.CommandLine, placeholder string) bool {
+	for _, part := range command.Strings() {
+		if strings.Contains(part, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// Indicates if an occurrence of the `placeholder` in the command `line` is
+// enclosed in double quotes.
+func isQuoted(line, placeholder string) bool {
+	for offset := 0; ; {
+		index := strings.Index(line[offset:], placeholder)
+		if index < 0 {
+			return false
+		}
+		if strings.Count(line[:offset+index], `"`)%2 == 1 {
+			return true
+		}
+		offset += index + len(placeholder)
+	}
+}
+
+// Indicates if the given `command` signs many files at once.
+func isBatchCommand(command common.CommandLine) bool {
+	return containsPlaceholder(command, binaryPathsPlaceholder)
+}
+
+// Substitutes the values of the placeholders into the `template`. If `quote` is set,
+// the values with spaces or special `cmd` characters are enclosed in double quotes
+// unless the placeholder is preceded by a double quote.
+func substitutePlaceholders(template string, values []placeholderValue, quote bool) string {
+	for _, value := range values {
+		var result strings.Builder
+		rest := template
+		for {
+			index := strings.Index(rest, value.placeholder)
+			if index < 0 {
+				break
+			}
+			result.WriteString(rest[:index])
+			if quote && !strings.HasSuffix(result.String(), `"`) {
+				result.WriteString(quoteForCmd(value.value))
+			} else {
+				result.WriteString(value.value)
+			}
+			rest = rest[index+len(value.placeholder):]
+		}
+		result.WriteString(rest)
+		template = result.String()
+	}
+	return template
+}
+
+// Encloses the `value` in double quotes if it contains spaces or special `cmd` characters.
+func quoteForCmd(value string) string {
+	if strings.ContainsAny(value, " \t&|<>^()") {
+		return `"` + value + `"`
+	}
+	return value
 }
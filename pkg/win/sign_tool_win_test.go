@@ -0,0 +1,164 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+func TestSubstitutePlaceholders(t *testing.T) {
+	values := []placeholderValue{
+		{binaryFilePathPlaceholder, `C:\Program Files\App\app.exe`},
+		{fileNamePlaceholder, "app.exe"},
+		{productNamePlaceholder, "R&D App"},
+	}
+	tests := []struct {
+		template string
+		quote    bool
+		want     string
+	}{
+		{`sign @@BINARY_PATH@@`, false, `sign C:\Program Files\App\app.exe`},
+		{`sign @@BINARY_PATH@@`, true, `sign "C:\Program Files\App\app.exe"`},
+		{`sign "@@BINARY_PATH@@"`, true, `sign "C:\Program Files\App\app.exe"`},
+		{`sign /d @@PRODUCT_NAME@@ @@FILE_NAME@@`, true, `sign /d "R&D App" app.exe`},
+		{`@@FILE_NAME@@.sig @@FILE_NAME@@`, true, `app.exe.sig app.exe`},
+		{`sign @@PRODUCT_URL@@`, true, `sign @@PRODUCT_URL@@`},
+	}
+	for _, test := range tests {
+		if got := substitutePlaceholders(test.template, values, test.quote); got != test.want {
+			t.Errorf("substitutePlaceholders(%q, quote: %v) = %q, want %q", test.template, test.quote, got, test.want)
+		}
+	}
+}
+
+func TestGetSignToolWinRejectsQuotedBinaryPaths(t *testing.T) {
+	tests := []struct {
+		command common.CommandLine
+		wantErr bool
+	}{
+		{common.CommandLine{Line: `signtool sign /d "@@PRODUCT_NAME@@" @@BINARY_PATHS@@`}, false},
+		{common.CommandLine{Argv: []string{"signtool", "sign", "@@BINARY_PATHS@@"}}, false},
+		{common.CommandLine{Line: `signtool sign "@@BINARY_PATHS@@"`}, true},
+		{common.CommandLine{Line: `signtool sign /d "@@PRODUCT_NAME@@ @@BINARY_PATHS@@"`}, true},
+	}
+	for _, test := range tests {
+		params := common.BrandingParams{Win: common.Win{SignCommand: &common.SignCommand{
+			Commands: []common.GlobCommand{{Glob: "*", Command: test.command}},
+		}}}
+		_, err := GetSignToolWin(params)
+		if test.wantErr && err == nil {
+			t.Errorf("GetSignToolWin accepted %q", test.command.Strings())
+		} else if !test.wantErr && err != nil {
+			t.Errorf("GetSignToolWin rejected %q: %v", test.command.Strings(), err)
+		}
+	}
+}
+
+// testPE returns a PE32+ file without sections whose certificate table holds
+// the given data, or no certificate table if the data is nil.
+func testPE(certificateTable []byte) []byte {
+	const peOffset = 0x40
+	optionalHeaderOffset := peOffset + len(peSignature) + coffHeaderSize
+	dataDirectoryOffset := optionalHeaderOffset + dataDirectories64Field
+	data := make([]byte, dataDirectoryOffset+16*dataDirectoryEntrySize)
+	copy(data, dosSignature)
+	binary.LittleEndian.PutUint32(data[peHeaderOffsetField:], peOffset)
+	copy(data[peOffset:], peSignature)
+	binary.LittleEndian.PutUint16(data[peOffset+len(peSignature)+16:], uint16(len(data)-optionalHeaderOffset))
+	binary.LittleEndian.PutUint16(data[optionalHeaderOffset:], optionalHeaderMagic64)
+	binary.LittleEndian.PutUint32(data[dataDirectoryOffset-4:], 16)
+	if certificateTable != nil {
+		securityOffset := dataDirectoryOffset + dataDirectorySecurity*dataDirectoryEntrySize
+		binary.LittleEndian.PutUint32(data[securityOffset:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(data[securityOffset+4:], uint32(len(certificateTable)))
+		data = append(data, certificateTable...)
+	}
+	return data
+}
+
+func TestCheckSignedRequiresNewSignature(t *testing.T) {
+	dir := t.TempDir()
+	unsignedPath := filepath.Join(dir, "unsigned.dll")
+	resignedPath := filepath.Join(dir, "resigned.dll")
+	skippedPath := filepath.Join(dir, "skipped.dll")
+	for path, data := range map[string][]byte{
+		unsignedPath: testPE(nil),
+		resignedPath: testPE([]byte("old signature")),
+		skippedPath:  testPE([]byte("old signature")),
+	} {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	binaryPaths := []string{unsignedPath, resignedPath, skippedPath}
+	previousHashes := [][]byte{}
+	for _, binaryPath := range binaryPaths {
+		hash, err := certificateTableHash(binaryPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		previousHashes = append(previousHashes, hash)
+	}
+
+	// The sign command signs the unsigned file, re-signs one of the signed
+	// files, and leaves the other one as is.
+	for _, binaryPath := range []string{unsignedPath, resignedPath} {
+		if err := os.WriteFile(binaryPath, testPE([]byte("new signature")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := checkSigned(binaryPaths, previousHashes)
+	if err == nil {
+		t.Fatal("checkSigned accepted a file with the previous signature")
+	}
+	if want := "the sign command has not signed " + skippedPath; err.Error() != want {
+		t.Errorf("checkSigned() = %q, want %q", err, want)
+	}
+	if err := checkSigned(binaryPaths[:2], previousHashes[:2]); err != nil {
+		t.Errorf("checkSigned rejected the new signatures: %v", err)
+	}
+}
+
+func TestBatchesSplitsByBatchSize(t *testing.T) {
+	batchSize := 2
+	params := common.BrandingParams{Win: common.Win{
+		SignCommand: &common.SignCommand{Commands: []common.GlobCommand{
+			{Glob: "*.dll", Command: common.CommandLine{Argv: []string{"signtool", "sign", binaryPathsPlaceholder}}},
+			{Glob: "*", Command: common.CommandLine{Argv: []string{"signtool", "sign", binaryFilePathPlaceholder}}},
+		}},
+		SignBatchSize: &batchSize,
+	}}
+	tool, err := GetSignToolWin(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := tool.Batches([]string{"a.dll", "app.exe", "b.dll", "c.dll"})
+	want := [][]string{{"app.exe"}, {"a.dll", "b.dll"}, {"c.dll"}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("Batches() = %q, want %q", batches, want)
+	}
+}
@@ -21,6 +21,8 @@
 package win
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -73,3 +75,21 @@ func RemoveSignature(binary base.File) (UnsignedBinary, error) {
 	}
 	return UnsignedBinary{binary}, nil
 }
+
+// certificateTableHash returns the SHA-256 hash of the certificate table
+// of the PE file, or nil if the file has no embedded Authenticode signature.
+func certificateTableHash(binaryPath string) ([]byte, error) {
+	pe, err := readPEFile(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	offset, size := pe.dataDirectory(dataDirectorySecurity)
+	if size == 0 {
+		return nil, nil
+	}
+	if int(offset)+int(size) > len(pe.data) {
+		return nil, errors.New("the security directory exceeds the file size")
+	}
+	hash := sha256.Sum256(pe.data[offset : offset+size])
+	return hash[:], nil
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	versionInfoKey         = "VS_VERSION_INFO"
+	stringFileInfoKey      = "StringFileInfo"
+	varFileInfoKey         = "VarFileInfo"
+	translationKey         = "Translation"
+	versionInfoHeaderSize  = 6
+	versionInfoTextType    = 1
+	unicodeCodePage        = 1200
+	versionInfoStructAlign = 4
+
+	// The VS_FIXEDFILEINFO structure and the offsets of its version fields.
+	fixedFileInfoSignature    = 0xFEEF04BD
+	fixedFileInfoSize         = 52
+	fixedFileVersionOffset    = 8
+	fixedProductVersionOffset = 16
+)
+
+// versionInfoNode is a structure of the VERSIONINFO resource: VS_VERSIONINFO,
+// StringFileInfo, StringTable, String, VarFileInfo, or Var. Every structure
+// has a key, an optional value, and child structures.
+type versionInfoNode struct {
+	key string
+
+	// isText reports whether the value is a null-terminated UTF-16 string.
+	isText bool
+
+	// value holds the raw bytes of the value, including the null terminator
+	// of a text value.
+	value []byte
+
+	children []*versionInfoNode
+}
+
+// parseVersionInfo parses the data of an RT_VERSION resource.
+func parseVersionInfo(data []byte) (*versionInfoNode, error) {
+	root, _, err := parseVersionInfoNode(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version information: %w", err)
+	}
+	if root.key != versionInfoKey {
+		return nil, fmt.Errorf("invalid version information: unexpected key %q", root.key)
+	}
+	return root, nil
+}
+
+// parseVersionInfoNode parses the structure at the offset and returns it
+// with the offset of its end.
+func parseVersionInfoNode(data []byte, offset int) (*versionInfoNode, int, error) {
+	if offset+versionInfoHeaderSize > len(data) {
+		return nil, 0, errors.New("truncated structure")
+	}
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	valueLength := int(binary.LittleEndian.Uint16(data[offset+2:]))
+	node := &versionInfoNode{isText: binary.LittleEndian.Uint16(data[offset+4:]) == versionInfoTextType}
+	end := offset + length
+	if length < versionInfoHeaderSize || end > len(data) {
+		return nil, 0, errors.New("invalid structure length")
+	}
+
+	position := offset + versionInfoHeaderSize
+	key := []uint16{}
+	for ; ; position += 2 {
+		if position+2 > end {
+			return nil, 0, errors.New("unterminated key")
+		}
+		char := binary.LittleEndian.Uint16(data[position:])
+		if char == 0 {
+			position += 2
+			break
+		}
+		key = append(key, char)
+	}
+	node.key = string(utf16.Decode(key))
+	position = alignVersionInfo(position)
+
+	// The length of a text value is in characters, although some resource
+	// compilers write it in bytes, so the value is clamped to the structure.
+	valueSize := valueLength
+	if node.isText {
+		valueSize *= 2
+	}
+	if valueSize > 0 {
+		if position+valueSize > end {
+			valueSize = end - position
+		}
+		if valueSize < 0 {
+			return nil, 0, errors.New("invalid value length")
+		}
+		node.value = append([]byte(nil), data[position:position+valueSize]...)
+		if node.isText {
+			// String structures have no children.
+			return node, end, nil
+		}
+		position = alignVersionInfo(position + valueSize)
+	}
+
+	for position+versionInfoHeaderSize <= end {
+		child, childEnd, err := parseVersionInfoNode(data, position)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", node.key, err)
+		}
+		node.children = append(node.children, child)
+		position = alignVersionInfo(childEnd)
+	}
+	return node, end, nil
+}
+
+// serialize encodes the structure with its children. The returned data is
+// not padded after the last child.
+func (node *versionInfoNode) serialize() []byte {
+	data := make([]byte, versionInfoHeaderSize)
+	data = append(data, encodeUTF16String(node.key)...)
+	data = padVersionInfo(data)
+	data = append(data, node.value...)
+	for _, child := range node.children {
+		data = padVersionInfo(data)
+		data = append(data, child.serialize()...)
+	}
+
+	valueLength := len(node.value)
+	valueType := uint16(0)
+	if node.isText {
+		valueLength /= 2
+		valueType = versionInfoTextType
+	}
+	binary.LittleEndian.PutUint16(data[0:], uint16(len(data)))
+	binary.LittleEndian.PutUint16(data[2:], uint16(valueLength))
+	binary.LittleEndian.PutUint16(data[4:], valueType)
+	return data
+}
+
+// setFixedVersion sets the file or the product version in the VS_FIXEDFILEINFO
+// structure of the VS_VERSIONINFO structure. The missing version parts are zeros.
+func (node *versionInfoNode) setFixedVersion(offset int, version string) error {
+	if len(node.value) < fixedFileInfoSize || binary.LittleEndian.Uint32(node.value) != fixedFileInfoSignature {
+		return errors.New("the version information has no fixed file information")
+	}
+	parts := [4]uint16{}
+	for i, part := range strings.Split(version, ".") {
+		value, err := strconv.ParseUint(part, 10, 16)
+		if err != nil || i >= len(parts) {
+			return fmt.Errorf("invalid version %s", version)
+		}
+		parts[i] = uint16(value)
+	}
+	binary.LittleEndian.PutUint32(node.value[offset:], uint32(parts[0])<<16|uint32(parts[1]))
+	binary.LittleEndian.PutUint32(node.value[offset+4:], uint32(parts[2])<<16|uint32(parts[3]))
+	return nil
+}
+
+// child returns the child structure with the given key, or nil.
+func (node *versionInfoNode) child(key string) *versionInfoNode {
+	for _, child := range node.children {
+		if strings.EqualFold(child.key, key) {
+			return child
+		}
+	}
+	return nil
+}
+
+// text returns the text value of a String structure.
+func (node *versionInfoNode) text() string {
+	chars := []uint16{}
+	for i := 0; i+1 < len(node.value); i += 2 {
+		char := binary.LittleEndian.Uint16(node.value[i:])
+		if char == 0 {
+			break
+		}
+		chars = append(chars, char)
+	}
+	return string(utf16.Decode(chars))
+}
+
+// setString sets the value of the String structure with the given key in
+// a StringTable structure, adding the String structure if necessary.
+func (node *versionInfoNode) setString(key, value string) {
+	if child := node.child(key); child != nil {
+		child.isText = true
+		child.value = encodeUTF16String(value)
+		return
+	}
+	node.children = append(node.children, newVersionString(key, value))
+}
+
+// newVersionString creates a String structure.
+func newVersionString(key, value string) *versionInfoNode {
+	return &versionInfoNode{key: key, isText: true, value: encodeUTF16String(value)}
+}
+
+// encodeUTF16String encodes the string as null-terminated UTF-16LE.
+func encodeUTF16String(value string) []byte {
+	chars := append(utf16.Encode([]rune(value)), 0)
+	data := make([]byte, 2*len(chars))
+	for i, char := range chars {
+		binary.LittleEndian.PutUint16(data[2*i:], char)
+	}
+	return data
+}
+
+func alignVersionInfo(offset int) int {
+	return (offset + versionInfoStructAlign - 1) &^ (versionInfoStructAlign - 1)
+}
+
+func padVersionInfo(data []byte) []byte {
+	return append(data, make([]byte, alignVersionInfo(len(data))-len(data))...)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026 TeamDev
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package win
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TeamDev-IP/Chromium-Branding/pkg/common"
+)
+
+const (
+	fileDescriptionVersionString  = "FileDescription"
+	authorVersionString           = "CompanyName"
+	productNameVersionString      = "ProductName"
+	copyrightVersionString        = "LegalCopyright"
+	fileVersionVersionString      = "FileVersion"
+	productVersionVersionString   = "ProductVersion"
+	originalFilenameVersionString = "OriginalFilename"
+	internalNameVersionString     = "InternalName"
+)
+
+// standardVersionStrings lists the keys of the StringFileInfo table
+// documented for the VERSIONINFO resource.
+var standardVersionStrings = []string{
+	"Comments",
+	authorVersionString,
+	fileDescriptionVersionString,
+	fileVersionVersionString,
+	internalNameVersionString,
+	copyrightVersionString,
+	"LegalTrademarks",
+	originalFilenameVersionString,
+	"PrivateBuild",
+	productNameVersionString,
+	productVersionVersionString,
+	"SpecialBuild",
+}
+
+// versionStringsToSet returns the StringFileInfo values to set in the
+// executable in addition to the ones set by the dedicated parameters.
+// OriginalFilename and InternalName follow params.Win.ExecutableName
+// unless params.Win.VersionStrings sets them explicitly.
+func versionStringsToSet(params *common.BrandingParams) map[string]string {
+	versionStrings := map[string]string{}
+	if params.Win.ExecutableName != nil {
+		versionStrings[originalFilenameVersionString] = *params.Win.ExecutableName + ".exe"
+		versionStrings[internalNameVersionString] = *params.Win.ExecutableName
+	}
+
+	dedicatedParams := map[string]*string{
+		fileDescriptionVersionString: params.Win.ProcessDisplayName,
+		authorVersionString:          params.Win.Author,
+		productNameVersionString:     params.Win.ProductName,
+		copyrightVersionString:       params.Win.LegalCopyright,
+	}
+	for key, value := range params.Win.VersionStrings {
+		key = canonicalVersionStringKey(key)
+		if dedicatedParam := dedicatedParams[key]; dedicatedParam != nil && *dedicatedParam != value {
+			fmt.Printf("WARNING: win.versionStrings overrides %s set by another parameter.\n", key)
+		}
+		versionStrings[key] = value
+	}
+	return versionStrings
+}
+
+// canonicalVersionStringKey returns the standard spelling of the key
+// if it differs from a standard key only in case.
+func canonicalVersionStringKey(key string) string {
+	for _, standardKey := range standardVersionStrings {
+		if strings.EqualFold(key, standardKey) {
+			if key != standardKey {
+				fmt.Printf("WARNING: using the version string key %s instead of %s.\n", standardKey, key)
+			}
+			return standardKey
+		}
+	}
+	return key
+}
+
+// setVersionStrings sets the StringFileInfo values in every StringTable
+// structure of the version information.
+func setVersionStrings(root *versionInfoNode, versionStrings map[string]string) error {
+	stringFileInfo := root.child(stringFileInfoKey)
+	if stringFileInfo == nil || len(stringFileInfo.children) == 0 {
+		return errors.New("the version information has no string table")
+	}
+	keys := []string{}
+	for key := range versionStrings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, table := range stringFileInfo.children {
+		for _, key := range keys {
+			table.setString(key, versionStrings[key])
+		}
+	}
+	return nil
+}
+
+// fileDescription returns the FileDescription of the binary, or an empty
+// string if the binary has no version information.
+func fileDescription(binaryPath string) string {
+	description, _ := readVersionString(binaryPath, fileDescriptionVersionString)
+	return description
+}
+
+// readVersionString returns the StringFileInfo value with the given key from
+// the first string table of the binary, and whether the binary is a PE file
+// with the version information.
+func readVersionString(binaryPath, key string) (string, bool) {
+	pe, err := readPEFile(binaryPath)
+	if err != nil {
+		return "", false
+	}
+	resources, err := pe.readResources()
+	if err != nil {
+		return "", false
+	}
+	versions := resources.ofType(intResourceID(resourceTypeVersion))
+	for _, version := range versions {
+		root, err := parseVersionInfo(version.data)
+		if err != nil {
+			continue
+		}
+		if stringFileInfo := root.child(stringFileInfoKey); stringFileInfo != nil && len(stringFileInfo.children) != 0 {
+			if value := stringFileInfo.children[0].child(key); value != nil {
+				return value.text(), true
+			}
+		}
+	}
+	return "", len(versions) != 0
+}
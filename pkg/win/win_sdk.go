@@ -28,7 +28,6 @@ import (
 )
 
 const defaultWinSdksInstallPath = "C:\\Program Files (x86)\\Windows Kits"
-const supportedArch = "x64"
 
 var availableSdk *WinSdk
 
@@ -87,8 +86,9 @@ func WinSdkFromPathEnv() (*WinSdk, error) {
 }
 
 // DefaultWinSdk searches the default Windows SDK install path for a Windows 10/11 SDK.
-// It looks for a directory structure matching "bin/10/<version>/<supportedArch>" and returns
-// the latest valid WinSdk found. If none is found, an error with installation instructions is returned.
+// It looks for a directory structure matching "bin/10/<version>/<arch>", where arch is one of
+// hostArchitectures, and returns the latest valid WinSdk found, preferring the native tools.
+// If none is found, an error with installation instructions is returned.
 func DefaultWinSdk() (*WinSdk, error) {
 	defaultWinSdksInstallDir, err := base.DirectoryFromPathString(defaultWinSdksInstallPath)
 	if err != nil {
@@ -101,7 +101,7 @@ func DefaultWinSdk() (*WinSdk, error) {
 		versions := sdkVersionsDir.ChildDirs()
 		sort.SliceStable(versions, func(i, j int) bool { return versions[i].AbsPath().Base() > versions[j].AbsPath().Base() })
 		for _, version := range versions {
-			if sdkBinPath, err := version.AbsPath().Join(base.RelPathFromEntries(supportedArch)).AsDirectory(); err == nil {
+			if sdkBinPath, err := findSdkBinDir(version); err == nil {
 				return &WinSdk{sdkBinPath}, nil
 			} else {
 				fmt.Printf("WARNING: Windows SDK of version %s has been found, but contains no binaries for the appropriate architecure.\n", version.AbsPath().Base())
@@ -112,7 +112,21 @@ func DefaultWinSdk() (*WinSdk, error) {
 	return nil, fmt.Errorf(`
 	cannot find any Windows SDKs in the default install location: %s.
 	Please, install up-to date windows SDK https://developer.microsoft.com/en-us/windows/downloads/windows-sdk/
-	or add the appropriate bin directory (e. g. %s\10\bin\10.0.26100.0\x64) to PATH
+	or add the appropriate bin directory (e. g. %s\10\bin\10.0.26100.0\%s) to PATH
 	if you already have Windows SDK installed to the custom location.
-	`, defaultWinSdksInstallPath, defaultWinSdksInstallPath)
+	`, defaultWinSdksInstallPath, defaultWinSdksInstallPath, hostArchitectures()[0])
+}
+
+// findSdkBinDir returns the bin directory of the SDK version with the tools
+// runnable on this machine, preferring the native ones.
+func findSdkBinDir(version base.Directory) (base.Directory, error) {
+	var lastErr error
+	for _, arch := range hostArchitectures() {
+		sdkBinPath, err := version.AbsPath().Join(base.RelPathFromEntries(arch)).AsDirectory()
+		if err == nil {
+			return sdkBinPath, nil
+		}
+		lastErr = err
+	}
+	return base.Directory{}, lastErr
 }